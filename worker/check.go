@@ -0,0 +1,57 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Go Worker Config Check (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// --check-config: load and print the effective config, verify keys/TLS
+// material, and probe TS reachability. Exit 0 on success, 1 on failure.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/gemimi2525-star/super-platform/worker/config"
+	"github.com/gemimi2525-star/super-platform/worker/worker"
+)
+
+// runCheckConfig validates configuration and returns the process exit code.
+func runCheckConfig() int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: FAIL — %v\n", err)
+		return 1
+	}
+
+	fmt.Println("Effective configuration:")
+	cfg.WriteSummary(os.Stdout)
+	fmt.Println()
+
+	// worker.New decodes the public key and loads TLS material. Checking
+	// has no side effects: no crash recovery (it would report WAL entries
+	// to TS), and no audit, recording, spool or tracing state is created
+	cfg.CrashRecovery = false
+	cfg.AuditEnabled = false
+	cfg.OTelEnabled = false
+	cfg.RecordEnvelopesDir = ""
+	cfg.ResultSpoolDir = ""
+	quiet := slog.New(slog.NewTextHandler(io.Discard, nil))
+	w, err := worker.New(cfg, quiet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "worker init: FAIL — %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPTimeout)
+	defer cancel()
+	if err := w.ProbeAPI(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "api reachability: FAIL — %v\n", err)
+		return 1
+	}
+
+	fmt.Println("config: OK")
+	return 0
+}
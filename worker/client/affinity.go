@@ -0,0 +1,16 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Claim Affinity (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Claiming with "affinity": true asks TS to route jobs sharing an
+// affinityKey to the same worker. Best-effort only: handlers must not
+// depend on having seen earlier jobs with the same key.
+
+package client
+
+// WithAffinity sends "affinity": true with every claim when enabled.
+func WithAffinity(enabled bool) Option {
+	return func(c *APIClient) {
+		c.affinity = enabled
+	}
+}
@@ -3,86 +3,516 @@
 // ═══════════════════════════════════════════════════════════════════════════
 //
 // HTTP client for communicating with TS Core OS.
-// Supports: claim, result, heartbeat.
+// Supports: claim, claim-batch, peek, result, result-stream, heartbeat, release.
+// Transient failures are retried with backoff (see retry.go); error
+// statuses are returned as *APIError (see errors.go).
 
 package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gemimi2525-star/super-platform/worker/contracts"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+	"github.com/gemimi2525-star/super-platform/worker/version"
 )
 
 // APIClient communicates with TS Core OS endpoints.
 type APIClient struct {
-	baseURL    string
+	endpoints []string // primary first, then standbys (see failover.go)
+
+	// Dedicated base URLs for result posts and heartbeats ("" = endpoints)
+	resultURL    string
+	heartbeatURL string
+
 	httpClient *http.Client
+	transport  *http.Transport
+	retry      RetryPolicy
+	logger     *slog.Logger
+	w3cTrace   bool
+	ackSecret  string // non-empty = require a signed ack from the result endpoint
+	authToken  string // sent as Authorization: Bearer <token>
+	workerID   string // sent as X-Worker-Id when set
+	userAgent  string
+	posted     *postedKeys
+	metrics    metrics.Metrics
+	breaker    *circuitBreaker // nil = disabled
+
+	legacy204 sync.Once // the 204 deprecation warning is logged once
+
+	authFailing atomic.Bool // set while TS rejects our credentials (see auth.go)
+
+	active         atomic.Int32 // index into endpoints of the last-good TS
+	primaryChecked atomic.Int64 // unix nanos of the last primary re-probe
+
+	// maxJobBytes caps the body read per claimed job (0 = unlimited)
+	maxJobBytes int64
+
+	// compressMin gzips result bodies larger than this (0 = off)
+	compressMin int
+
+	// minPriority sent with every claim (0 = no filter)
+	minPriority int
+
+	// affinity-routed jobs accepted (see affinity.go)
+	affinity bool
+
+	// "busy" claim hint (see SetBusyHint)
+	busy atomic.Bool
+
+	// Lease length requested with every claim (0 = TS default) and per-jobType overrides
+	visibilitySeconds int
+	jobTypeVisibility map[string]int
+
+	// jobType filter sent with every claim so TS only hands out runnable jobs
+	allowTypes []string
+	denyTypes  []string
+
+	// static headers sent on every request (EXTRA_HTTP_HEADERS)
+	extra http.Header
+}
+
+// Request metrics recorded by send.
+const (
+	MetricRequests       = "worker_api_requests_total"     // by path and status ("error" = no response)
+	MetricRequestLatency = "worker_api_request_latency_ms" // per attempt, including failures
+	MetricClaimResponses = "worker_claim_responses_total"  // by path and result (see recordClaim)
+)
+
+// HeaderWorkerID identifies the calling worker on outbound requests.
+const HeaderWorkerID = "X-Worker-Id"
+
+// Option configures optional APIClient behaviour.
+type Option func(*APIClient)
+
+// WithRetry overrides the default retry policy for transient failures.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *APIClient) {
+		c.retry = policy
+	}
+}
+
+// WithLogger sets the logger used for retry diagnostics.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *APIClient) {
+		c.logger = logger
+	}
+}
+
+// WithUserAgent overrides the User-Agent sent to TS
+// (default: version.UserAgent(), "coreos-worker/<version> (<go version>)").
+func WithUserAgent(ua string) Option {
+	return func(c *APIClient) {
+		c.userAgent = ua
+	}
+}
+
+// WithMetrics sets where request metrics are recorded (default: discarded).
+func WithMetrics(m metrics.Metrics) Option {
+	return func(c *APIClient) {
+		c.metrics = m
+	}
+}
+
+// SetMetrics is WithMetrics for an existing client. Not safe to call while
+// requests are in flight.
+func (c *APIClient) SetMetrics(m metrics.Metrics) {
+	c.metrics = m
+}
+
+// WithW3CTrace enables a W3C traceparent header alongside X-Trace-Id.
+func WithW3CTrace(enabled bool) Option {
+	return func(c *APIClient) {
+		c.w3cTrace = enabled
+	}
+}
+
+// WithResultAck requires TS to return a ResultAck signed with secret
+// for every posted result.
+func WithResultAck(secret string) Option {
+	return func(c *APIClient) {
+		c.ackSecret = secret
+	}
+}
+
+// WithAuthToken sends Authorization: Bearer <token> on every request.
+func WithAuthToken(token string) Option {
+	return func(c *APIClient) {
+		c.authToken = token
+	}
+}
+
+// WithExtraHeaders sends static headers on every request (EXTRA_HTTP_HEADERS).
+// Headers the client sets itself, and per-request ones, take precedence.
+func WithExtraHeaders(headers map[string]string) Option {
+	return func(c *APIClient) {
+		c.extra = make(http.Header, len(headers))
+		for name, value := range headers {
+			c.extra.Set(name, value)
+		}
+	}
+}
+
+// WithWorkerIDHeader sends X-Worker-Id on every request.
+func WithWorkerIDHeader(workerID string) Option {
+	return func(c *APIClient) {
+		c.workerID = workerID
+	}
+}
+
+// WithJobTypeFilter restricts claims to allowed jobTypes (empty = all)
+// and excludes denied ones.
+func WithJobTypeFilter(allow, deny []string) Option {
+	return func(c *APIClient) {
+		c.allowTypes = allow
+		c.denyTypes = deny
+	}
+}
+
+// SetJobTypeFilter replaces the claim jobType filter for later claims.
+// Not safe to call concurrently with a claim.
+func (c *APIClient) SetJobTypeFilter(allow, deny []string) {
+	c.allowTypes = allow
+	c.denyTypes = deny
+}
+
+// SetBusyHint sends (or stops sending) "busy": true with later claims,
+// telling TS the worker has been saturated and may be deprioritized.
+func (c *APIClient) SetBusyHint(busy bool) {
+	c.busy.Store(busy)
+}
+
+// WithMinPriority only claims jobs with priority >= min.
+func WithMinPriority(min int) Option {
+	return func(c *APIClient) {
+		c.minPriority = min
+	}
+}
+
+// WithVisibilityTimeout requests a lease of seconds per claim, or
+// perJobType[jobType] seconds for the listed jobTypes.
+func WithVisibilityTimeout(seconds int, perJobType map[string]int) Option {
+	return func(c *APIClient) {
+		c.visibilitySeconds = seconds
+		if len(perJobType) > 0 {
+			c.jobTypeVisibility = perJobType
+		}
+	}
+}
+
+// WithConnPool tunes connection reuse to TS. All workers talk to a single
+// host, so the per-host idle limit matches maxIdle (the default of 2 would
+// churn connections under load). maxPerHost 0 = unlimited.
+func WithConnPool(maxIdle, maxPerHost int, idleTimeout time.Duration) Option {
+	return func(c *APIClient) {
+		c.transport.MaxIdleConns = maxIdle
+		c.transport.MaxIdleConnsPerHost = maxIdle
+		c.transport.MaxConnsPerHost = maxPerHost
+		c.transport.IdleConnTimeout = idleTimeout
+	}
+}
+
+// WithDialTimeouts bounds connecting to TS (dial) and the TLS handshake on
+// new connections, separately from the overall request timeout, so a dead
+// host fails fast without cutting off slow body reads. 0 keeps the default.
+func WithDialTimeouts(dial, tlsHandshake time.Duration) Option {
+	return func(c *APIClient) {
+		if dial > 0 {
+			dialer := &net.Dialer{Timeout: dial, KeepAlive: 30 * time.Second}
+			c.transport.DialContext = dialer.DialContext
+		}
+		if tlsHandshake > 0 {
+			c.transport.TLSHandshakeTimeout = tlsHandshake
+		}
+	}
+}
+
+// WithMaxJobBytes caps how much of a claim response is read per job, so an
+// oversized payload fails the decode instead of exhausting memory.
+func WithMaxJobBytes(n int64) Option {
+	return func(c *APIClient) {
+		c.maxJobBytes = n
+	}
 }
 
 // NewAPIClient creates a new API client.
-func NewAPIClient(baseURL string, timeout time.Duration) *APIClient {
-	return &APIClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: timeout,
+func NewAPIClient(baseURL string, timeout time.Duration, opts ...Option) *APIClient {
+	c := &APIClient{
+		endpoints: []string{baseURL},
+		transport: http.DefaultTransport.(*http.Transport).Clone(),
+		retry: RetryPolicy{
+			MaxRetries: 3,
+			BaseDelay:  200 * time.Millisecond,
 		},
+		logger:    slog.Default(),
+		userAgent: version.UserAgent(),
+		posted:    newPostedKeys(DefaultResultDedupCacheSize),
+		metrics:   metrics.Nop,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.httpClient = &http.Client{
+		Timeout:   timeout,
+		Transport: c.transport,
+	}
+	return c
 }
 
 // JobEnvelope is the response from polling the queue.
 type JobEnvelope struct {
 	Ticket      contracts.JobTicket `json:"ticket"`
 	Payload     string              `json:"payload"`
+	Encoding    string              `json:"encoding,omitempty"`   // "" | "gzip+base64"
+	Encryption  string              `json:"encryption,omitempty"` // "" | "aes-256-gcm"
 	Version     string              `json:"version"`
 	Attempts    int                 `json:"attempts"`
 	MaxAttempts int                 `json:"maxAttempts"`
+	Priority    int                 `json:"priority,omitempty"`
+
+	// TraceParent is the W3C trace context of the TS span that enqueued the
+	// job, so worker spans nest under it (optional).
+	TraceParent string `json:"traceparent,omitempty"`
+
+	// LeaseDurationMs is the TS lease length; 0 if TS doesn't report it.
+	LeaseDurationMs int64 `json:"leaseDurationMs,omitempty"`
+
+	// HandlerVersion is the jobType handler version the job needs
+	// ("" = any version).
+	HandlerVersion string `json:"handlerVersion,omitempty"`
+
+	// AffinityKey groups related jobs TS tries to route to the same
+	// worker (see affinity.go); "" if the job has none.
+	AffinityKey string `json:"affinityKey,omitempty"`
 }
 
-// PollResponse is the response from the claim endpoint.
+// PollResponse is the response from the claim endpoint. Job is kept raw
+// so it can be validated field by field (see decodeEnvelopes).
 type PollResponse struct {
-	Job *JobEnvelope `json:"job"`
+	Job json.RawMessage `json:"job"`
+}
+
+// claimRequest is the body for claim, claim-batch, claim-longpoll and peek.
+type claimRequest struct {
+	WorkerID        string   `json:"workerId"`
+	Max             int      `json:"max,omitempty"`
+	MinPriority     int      `json:"minPriority,omitempty"`
+	JobTypes        []string `json:"jobTypes,omitempty"`
+	ExcludeJobTypes []string `json:"excludeJobTypes,omitempty"`
+	WaitSeconds     int      `json:"waitSeconds,omitempty"`
+	Affinity        bool     `json:"affinity,omitempty"`
+	Busy            bool     `json:"busy,omitempty"`
+
+	// Requested lease length; TS applies a jobType's override if present
+	VisibilityTimeoutSeconds        int            `json:"visibilityTimeoutSeconds,omitempty"`
+	JobTypeVisibilityTimeoutSeconds map[string]int `json:"jobTypeVisibilityTimeoutSeconds,omitempty"`
+}
+
+func (c *APIClient) newClaimRequest(workerID string, max int) []byte {
+	return c.newWaitClaimRequest(workerID, max, 0)
+}
+
+// newWaitClaimRequest is newClaimRequest with a long-poll wait hint.
+func (c *APIClient) newWaitClaimRequest(workerID string, max, waitSeconds int) []byte {
+	b, _ := json.Marshal(claimRequest{
+		WorkerID:        workerID,
+		Max:             max,
+		MinPriority:     c.minPriority,
+		JobTypes:        c.allowTypes,
+		ExcludeJobTypes: c.denyTypes,
+		WaitSeconds:     waitSeconds,
+		Affinity:        c.affinity,
+		Busy:            c.busy.Load(),
+
+		VisibilityTimeoutSeconds:        c.visibilitySeconds,
+		JobTypeVisibilityTimeoutSeconds: c.jobTypeVisibility,
+	})
+	return b
+}
+
+// BatchPollResponse is the response from the claim-batch endpoint; each
+// job is validated on its own so one malformed envelope doesn't drop the
+// whole batch.
+type BatchPollResponse struct {
+	Jobs []json.RawMessage `json:"jobs"`
+}
+
+// ErrBatchUnsupported is returned by ClaimBatch when TS has no
+// claim-batch endpoint (404), so callers can fall back to ClaimJob.
+var ErrBatchUnsupported = errors.New("claim-batch endpoint not supported by TS")
+
+// newRequest builds a request to path with the headers shared by all
+// TS calls (EXTRA_HTTP_HEADERS, content type, auth, worker identity,
+// build) plus any per-request ones.
+// The request is bound to ctx so cancellation aborts it in flight;
+// the client timeout remains as a backstop.
+func (c *APIClient) newRequest(ctx context.Context, method, path string, body []byte, header http.Header) (*http.Request, error) {
+	return c.newRequestTo(ctx, c.activeURL(), method, path, body, header)
+}
+
+// newRequestTo is newRequest against a specific TS endpoint.
+func (c *APIClient) newRequestTo(ctx context.Context, baseURL, method, path string, body []byte, header http.Header) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range c.extra {
+		req.Header[k] = v
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	if c.workerID != "" {
+		req.Header.Set(HeaderWorkerID, c.workerID)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set(HeaderContractVersion, ContractVersion)
+	return req, nil
+}
+
+// ResultResponse is the response from the result endpoint.
+type ResultResponse struct {
+	Ack *contracts.ResultAck `json:"ack,omitempty"`
+
+	// Disposition is what TS did with the result (one of the
+	// Disposition* values, or "" from a TS that doesn't report it).
+	Disposition string `json:"disposition,omitempty"`
 }
 
-// PostResult sends a signed JobResult to the TS Core OS.
-func (c *APIClient) PostResult(result *contracts.JobResult) error {
+// Result dispositions TS may report. Other values are passed through.
+const (
+	DispositionAccepted       = "ACCEPTED"        // recorded; the job is done
+	DispositionRetryScheduled = "RETRY_SCHEDULED" // FAILED result; TS will retry the job
+	DispositionDeadLettered   = "DEAD_LETTERED"   // FAILED result; no retries left
+)
+
+// maxResultResponseBytes bounds the result response read.
+const maxResultResponseBytes = 16 << 10
+
+// PostResult sends a signed JobResult to the TS Core OS and returns TS's
+// disposition of it ("" if TS didn't say).
+// When ack verification is enabled, a missing or invalid ack is treated
+// as a failed post and retried (TS dedupes results by Idempotency-Key).
+// Returns ErrResultAlreadyPosted if this job attempt was already posted.
+func (c *APIClient) PostResult(ctx context.Context, result *contracts.JobResult) (string, error) {
 	body, err := json.Marshal(result)
 	if err != nil {
-		return fmt.Errorf("failed to marshal result: %w", err)
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	key := idempotencyKey(result)
+	if err := c.reserveResult(result, key); err != nil {
+		return "", err
 	}
 
-	resp, err := c.httpClient.Post(
-		c.baseURL+"/api/jobs/result",
-		"application/json",
-		bytes.NewReader(body),
-	)
+	header := c.resultHeaders(result, key)
+	body = c.compressBody(body, header)
+	disposition, err := c.sendResult(ctx, result, "/api/jobs/result", body, header)
+	if err != nil {
+		c.posted.release(key) // not known to be committed; allow a later retry
+		return "", err
+	}
+	return disposition, nil
+}
+
+// resultHeaders returns the trace and idempotency headers for a result post.
+func (c *APIClient) resultHeaders(result *contracts.JobResult, key string) http.Header {
+	header := c.traceHeaders(result.TraceID)
+	header.Set("Idempotency-Key", key)
+	return header
+}
+
+// sendResult posts a result body to path, retrying invalid acks, and
+// returns the reported disposition.
+func (c *APIClient) sendResult(ctx context.Context, result *contracts.JobResult, path string, body []byte, header http.Header) (string, error) {
+	for attempt := 0; ; attempt++ {
+		disposition, err := c.postResultOnce(ctx, result, path, body, header)
+		if !errors.Is(err, errAckInvalid) || attempt >= c.retry.MaxRetries {
+			return disposition, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		c.logger.Warn("result ack invalid, retrying",
+			logging.KeyJobID, result.JobID,
+			logging.KeyError, err,
+			"delay", delay.String())
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// errAckInvalid marks a result post whose ack failed verification.
+var errAckInvalid = errors.New("result ack verification failed")
+
+// postResultOnce posts a result body once. Without ack verification a
+// body that isn't JSON (or is empty) is accepted with no disposition.
+func (c *APIClient) postResultOnce(ctx context.Context, result *contracts.JobResult, path string, body []byte, header http.Header) (string, error) {
+	resp, err := c.doWithRetry(ctx, path, body, header)
 	if err != nil {
-		return fmt.Errorf("failed to post result: %w", err)
+		return "", fmt.Errorf("failed to post result: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("result callback failed (status %d): %s", resp.StatusCode, string(respBody))
+		return "", newAPIError(path, resp)
 	}
 
-	return nil
+	var resultResp ResultResponse
+	decodeErr := json.NewDecoder(io.LimitReader(resp.Body, maxResultResponseBytes)).Decode(&resultResp)
+	if c.ackSecret == "" {
+		return resultResp.Disposition, nil
+	}
+
+	if decodeErr != nil {
+		return "", fmt.Errorf("%w: failed to decode response: %v", errAckInvalid, decodeErr)
+	}
+	if resultResp.Ack == nil {
+		return "", fmt.Errorf("%w: response has no ack", errAckInvalid)
+	}
+	if err := resultResp.Ack.Verify(result.JobID, c.ackSecret); err != nil {
+		return "", fmt.Errorf("%w: %v", errAckInvalid, err)
+	}
+	return resultResp.Disposition, nil
 }
 
 // ClaimJob calls POST /api/jobs/claim to atomically claim the next pending job.
-// Returns nil if no jobs are available.
-func (c *APIClient) ClaimJob(workerID string) (*JobEnvelope, error) {
-	reqBody, _ := json.Marshal(map[string]string{"workerId": workerID})
-
-	resp, err := c.httpClient.Post(
-		c.baseURL+"/api/jobs/claim",
-		"application/json",
-		bytes.NewReader(reqBody),
-	)
+// Returns nil if no jobs are available. Like the other claim calls, it
+// returns ErrCircuitOpen without sending anything while the breaker is open.
+func (c *APIClient) ClaimJob(ctx context.Context, workerID string) (*JobEnvelope, error) {
+	if err := c.allowClaim(); err != nil {
+		return nil, err
+	}
+	reqBody := c.newClaimRequest(workerID, 0)
+
+	resp, err := c.doWithRetry(ctx, "/api/jobs/claim", reqBody, nil)
 	if err != nil {
 		return nil, fmt.Errorf("claim request failed: %w", err)
 	}
@@ -90,43 +520,232 @@ func (c *APIClient) ClaimJob(workerID string) (*JobEnvelope, error) {
 
 	// 204 = no jobs available (legacy)
 	if resp.StatusCode == 204 {
+		c.recordClaim("/api/jobs/claim", resp.StatusCode, 0)
 		return nil, nil
 	}
 
 	if resp.StatusCode >= 400 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("claim failed (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, newAPIError("/api/jobs/claim", resp)
 	}
 
 	var pollResp PollResponse
-	if err := json.NewDecoder(resp.Body).Decode(&pollResp); err != nil {
+	if err := c.decodeLimited(resp.Body, c.maxJobBytes, &pollResp); err != nil {
 		return nil, fmt.Errorf("failed to decode claim response: %w", err)
 	}
+	c.recordClaim("/api/jobs/claim", resp.StatusCode, countJobs(pollResp.Job))
+
+	return c.claimedEnvelope(pollResp.Job), nil
+}
+
+// PeekJob calls POST /api/jobs/peek to fetch the next pending job without
+// taking a lease, so other workers can still claim it. Used by dry-run.
+// Returns nil if no jobs are available.
+func (c *APIClient) PeekJob(ctx context.Context, workerID string) (*JobEnvelope, error) {
+	if err := c.allowClaim(); err != nil {
+		return nil, err
+	}
+	reqBody := c.newClaimRequest(workerID, 0)
+
+	resp, err := c.doWithRetry(ctx, "/api/jobs/peek", reqBody, nil)
+	if err != nil {
+		return nil, fmt.Errorf("peek request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 204 {
+		c.recordClaim("/api/jobs/peek", resp.StatusCode, 0)
+		return nil, nil
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError("/api/jobs/peek", resp)
+	}
+
+	var pollResp PollResponse
+	if err := c.decodeLimited(resp.Body, c.maxJobBytes, &pollResp); err != nil {
+		return nil, fmt.Errorf("failed to decode peek response: %w", err)
+	}
+	c.recordClaim("/api/jobs/peek", resp.StatusCode, countJobs(pollResp.Job))
+
+	return c.claimedEnvelope(pollResp.Job), nil
+}
+
+// ClaimBatch calls POST /api/jobs/claim-batch to claim up to max pending jobs.
+// Returns an empty slice if no jobs are available, or ErrBatchUnsupported
+// if the endpoint does not exist on this TS version.
+func (c *APIClient) ClaimBatch(ctx context.Context, workerID string, max int) ([]JobEnvelope, error) {
+	if err := c.allowClaim(); err != nil {
+		return nil, err
+	}
+	reqBody := c.newClaimRequest(workerID, max)
+
+	resp, err := c.doWithRetry(ctx, "/api/jobs/claim-batch", reqBody, nil)
+	if err != nil {
+		return nil, fmt.Errorf("claim-batch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrBatchUnsupported
+	}
 
-	return pollResp.Job, nil
+	if resp.StatusCode == 204 {
+		c.recordClaim("/api/jobs/claim-batch", resp.StatusCode, 0)
+		return nil, nil
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError("/api/jobs/claim-batch", resp)
+	}
+
+	var batchResp BatchPollResponse
+	if err := c.decodeLimited(resp.Body, c.maxJobBytes*int64(max), &batchResp); err != nil {
+		return nil, fmt.Errorf("failed to decode claim-batch response: %w", err)
+	}
+	c.recordClaim("/api/jobs/claim-batch", resp.StatusCode, countJobs(batchResp.Jobs...))
+
+	return c.decodeEnvelopes(batchResp.Jobs...), nil
+}
+
+// recordClaim counts an empty or successful claim response by result:
+// "job" (at least one job, valid or not), "empty_200" (200 with no job, the
+// current contract) or "empty_204" (204 No Content, legacy). The first 204
+// also logs a deprecation warning, so the remaining TS versions sending it
+// can be found before 204 support is dropped.
+func (c *APIClient) recordClaim(path string, status, jobs int) {
+	result := "job"
+	switch {
+	case status == http.StatusNoContent:
+		result = "empty_204"
+		c.legacy204.Do(func() {
+			c.logger.Warn("TS answered an empty claim with 204 No Content; this is deprecated, TS should send 200 with no job",
+				"path", path)
+		})
+	case jobs == 0:
+		result = "empty_200"
+	}
+	c.metrics.IncrCounter(MetricClaimResponses, metrics.Labels{"path": path, "result": result})
+}
+
+// countJobs counts the non-null jobs in a claim response.
+func countJobs(raws ...json.RawMessage) int {
+	n := 0
+	for _, raw := range raws {
+		if !isNull(raw) {
+			n++
+		}
+	}
+	return n
+}
+
+// decodeLimited reads a claim response body in full and decodes it, failing
+// if it is larger than limit bytes (limit <= 0 = unlimited). An empty body
+// (200 with no content) leaves v untouched, i.e. no job. On a decode error
+// (e.g. a body truncated mid-stream) the error carries the body length and
+// a prefix of the raw bytes for diagnosis.
+func (c *APIClient) decodeLimited(body io.Reader, limit int64, v any) error {
+	r := body
+	if limit > 0 {
+		r = io.LimitReader(body, limit+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read response body (%d bytes so far): %w", len(data), err)
+	}
+	if limit > 0 && int64(len(data)) > limit {
+		return fmt.Errorf("response body exceeds %d bytes", limit)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w (body %d bytes: %q)", err, len(data), bodyPrefix(data))
+	}
+	return nil
+}
+
+// maxBodyPrefix bounds how much of an undecodable body goes into an error.
+const maxBodyPrefix = 256
+
+// bodyPrefix returns the start of data, marked when cut short.
+func bodyPrefix(data []byte) string {
+	if len(data) <= maxBodyPrefix {
+		return string(data)
+	}
+	return string(data[:maxBodyPrefix]) + "…"
+}
+
+// HeartbeatResponse is the heartbeat endpoint's reply. TS currently sends
+// only {jobId, leaseUntil}; Cancel and Reason are a hypothetical extension
+// (asking the worker to stop a superseded job, and why) that the worker
+// honours if TS ever sends them.
+type HeartbeatResponse struct {
+	Cancel bool   `json:"cancel,omitempty"`
+	Reason string `json:"reason,omitempty"`
 }
 
+// maxHeartbeatBodyBytes bounds the heartbeat response read.
+const maxHeartbeatBodyBytes = 4 << 10
+
 // Heartbeat sends a heartbeat to extend the lease for a running job.
-func (c *APIClient) Heartbeat(jobID, workerID string) error {
+// A body TS doesn't fill in (or that isn't JSON) is an empty response.
+func (c *APIClient) Heartbeat(ctx context.Context, jobID, workerID, traceID string) (*HeartbeatResponse, error) {
 	reqBody, _ := json.Marshal(map[string]string{
 		"jobId":    jobID,
 		"workerId": workerID,
 	})
 
-	resp, err := c.httpClient.Post(
-		c.baseURL+"/api/jobs/heartbeat",
-		"application/json",
-		bytes.NewReader(reqBody),
-	)
+	resp, err := c.doWithRetry(ctx, "/api/jobs/heartbeat", reqBody, c.traceHeaders(traceID))
+	if err != nil {
+		return nil, fmt.Errorf("heartbeat request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError("/api/jobs/heartbeat", resp)
+	}
+
+	var hb HeartbeatResponse
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxHeartbeatBodyBytes))
+	if len(body) > 0 {
+		json.Unmarshal(body, &hb)
+	}
+	return &hb, nil
+}
+
+// ReleaseJob returns a claimed job's lease to TS so it can be requeued
+// immediately instead of waiting for the lease to expire.
+func (c *APIClient) ReleaseJob(ctx context.Context, jobID, workerID string, attempts int) error {
+	reqBody, _ := json.Marshal(map[string]any{
+		"jobId":    jobID,
+		"workerId": workerID,
+		"attempts": attempts,
+	})
+
+	resp, err := c.doWithRetry(ctx, "/api/jobs/release", reqBody, nil)
 	if err != nil {
-		return fmt.Errorf("heartbeat request failed: %w", err)
+		return fmt.Errorf("release request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("heartbeat failed (status %d): %s", resp.StatusCode, string(respBody))
+		return newAPIError("/api/jobs/release", resp)
 	}
 
 	return nil
 }
+
+// Probe sends a HEAD request to the TS base URL to check reachability.
+// Any HTTP response counts as reachable; only transport errors fail.
+func (c *APIClient) Probe(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodHead, "", nil, nil)
+	if err != nil {
+		return fmt.Errorf("invalid API URL: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("TS unreachable: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
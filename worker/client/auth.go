@@ -0,0 +1,57 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Authentication Failures (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// TS answers 401 when the worker's credentials (WORKER_AUTH_TOKEN or its
+// client certificate) are invalid, expired or revoked, and 403 when the
+// claim or register endpoints refuse them. Those responses are counted,
+// logged once per outage and surfaced as ErrAuthFailed instead of a plain
+// status failure, so a credential problem isn't mistaken for a transient
+// TS outage. Other endpoints use 403 for request validation (a result TS
+// rejects, a heartbeat from a worker that doesn't hold the job), which
+// says nothing about the credentials.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+)
+
+// MetricAuthFailures counts requests TS rejected as unauthenticated, by path.
+const MetricAuthFailures = "worker_auth_failures_total"
+
+// ErrAuthFailed is matched (errors.Is) by an APIError for an authentication
+// failure (see authFailure).
+var ErrAuthFailed = errors.New("authentication failed: token invalid, expired or revoked")
+
+// authFailure reports whether resp to a request for path rejects the
+// worker's credentials: a 401 anywhere, or a 403 from claim or register.
+func authFailure(path string, resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusForbidden:
+		return claimPath(path) || path == "/api/workers/register"
+	}
+	return false
+}
+
+// checkAuth counts an authentication failure and logs when requests start
+// failing authentication, and again once TS accepts them.
+func (c *APIClient) checkAuth(path string, resp *http.Response) {
+	if !authFailure(path, resp) {
+		if resp.StatusCode < 400 && c.authFailing.Swap(false) {
+			c.logger.Info("authentication restored — TS accepted the worker's credentials", "path", path)
+		}
+		return
+	}
+	c.metrics.IncrCounter(MetricAuthFailures, metrics.Labels{"path": path})
+	if !c.authFailing.Swap(true) {
+		c.logger.Error("authentication failed — TS rejected the worker's credentials (token invalid, expired or revoked)",
+			"path", path,
+			"status", resp.StatusCode)
+	}
+}
@@ -0,0 +1,145 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — API Client Circuit Breaker (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// After CIRCUIT_BREAKER_THRESHOLD consecutive failed TS requests (transport
+// errors or 5xx) the breaker opens: claims fail fast with ErrCircuitOpen
+// and claim retries stop early, so a down TS isn't hammered. After the cooldown
+// it half-opens and lets one claim through as a probe; success closes it,
+// failure re-opens it for another cooldown. Heartbeats and result posts
+// are never blocked or cut short (they are bounded by their own retry
+// budgets), and their outcomes count towards the breaker like any other
+// request.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MetricBreakerState is the breaker state gauge: 0 closed, 1 half-open, 2 open.
+const MetricBreakerState = "worker_ts_breaker_state"
+
+// ErrCircuitOpen is returned by claim calls while the breaker is open.
+var ErrCircuitOpen = errors.New("TS circuit breaker open, claim skipped")
+
+// Breaker states.
+const (
+	breakerClosed   = 0
+	breakerHalfOpen = 1
+	breakerOpen     = 2
+)
+
+// circuitBreaker tracks consecutive TS request failures.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+	probeAt  time.Time // half-open probe in flight since (zero = none)
+}
+
+// WithCircuitBreaker opens the breaker after threshold consecutive failed
+// requests, for cooldown before probing again (threshold <= 0 = off).
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *APIClient) {
+		if threshold > 0 {
+			c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+		}
+	}
+}
+
+// allowClaim reports whether a claim may be sent; in half-open state only
+// one probe at a time is let through.
+func (c *APIClient) allowClaim() error {
+	b := c.breaker
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		c.setBreakerState(breakerHalfOpen)
+		c.logger.Info("TS circuit breaker half-open, probing with next claim")
+		fallthrough
+	case breakerHalfOpen:
+		// A probe whose outcome never arrived (e.g. cancelled) expires
+		if !b.probeAt.IsZero() && time.Since(b.probeAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.probeAt = time.Now()
+	}
+	return nil
+}
+
+// claimPath reports whether path is a claim request, the only kind the
+// breaker blocks or stops retrying.
+func claimPath(path string) bool {
+	switch path {
+	case "/api/jobs/claim", "/api/jobs/claim-batch", "/api/jobs/claim-longpoll", "/api/jobs/peek":
+		return true
+	}
+	return false
+}
+
+// breakerOpen reports whether the breaker is open (claim retries should stop).
+func (c *APIClient) breakerOpen() bool {
+	b := c.breaker
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == breakerOpen
+}
+
+// recordOutcome feeds one request result (resp nil on transport errors).
+func (c *APIClient) recordOutcome(resp *http.Response) {
+	b := c.breaker
+	if b == nil {
+		return
+	}
+	failed := resp == nil || resp.StatusCode >= 500
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probeAt = time.Time{}
+	if !failed {
+		b.failures = 0
+		if b.state != breakerClosed {
+			c.setBreakerState(breakerClosed)
+			c.logger.Info("TS circuit breaker closed, requests recovered")
+		}
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.state == breakerHalfOpen:
+		b.openedAt = time.Now()
+		c.setBreakerState(breakerOpen)
+		c.logger.Warn("TS circuit breaker probe failed, re-opening", "cooldown", b.cooldown.String())
+	case b.state == breakerClosed && b.failures >= b.threshold:
+		b.openedAt = time.Now()
+		c.setBreakerState(breakerOpen)
+		c.logger.Error("TS circuit breaker open, pausing claims",
+			"consecutiveFailures", b.failures,
+			"cooldown", b.cooldown.String())
+	}
+}
+
+// setBreakerState updates the state and its gauge. Callers hold b.mu.
+func (c *APIClient) setBreakerState(state int) {
+	c.breaker.state = state
+	c.metrics.SetGauge(MetricBreakerState, float64(state), nil)
+}
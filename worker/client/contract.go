@@ -0,0 +1,57 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Contract Version (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Every request declares the job contract the worker speaks in
+// X-Contract-Version. TS answers 415, or 409 naming its own version in the
+// same header, when it no longer accepts that contract; those responses
+// are logged and surfaced as ErrContractIncompatible instead of a plain
+// status failure, so schema drift between worker and TS is visible.
+
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ContractVersion is the TS job contract this worker implements.
+const ContractVersion = "22A"
+
+// HeaderContractVersion carries ContractVersion on requests (and TS's
+// version on mismatch responses).
+const HeaderContractVersion = "X-Contract-Version"
+
+// ErrContractIncompatible is matched (errors.Is) by an APIError for a
+// contract version mismatch response.
+var ErrContractIncompatible = errors.New("contract version incompatible")
+
+// contractMismatch reports whether resp rejects our contract version. A 409
+// only counts when TS names a different version, since it also signals
+// ordinary conflicts.
+func contractMismatch(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnsupportedMediaType:
+		return true
+	case http.StatusConflict:
+		v := resp.Header.Get(HeaderContractVersion)
+		return v != "" && v != ContractVersion
+	}
+	return false
+}
+
+// checkContract logs a contract version mismatch response from TS.
+func (c *APIClient) checkContract(path string, resp *http.Response) {
+	if !contractMismatch(resp) {
+		return
+	}
+	tsVersion := resp.Header.Get(HeaderContractVersion)
+	if tsVersion == "" {
+		tsVersion = "unknown"
+	}
+	c.logger.Error("contract version incompatible — TS rejected this worker's job contract",
+		"path", path,
+		"status", resp.StatusCode,
+		"workerContract", ContractVersion,
+		"tsContract", tsVersion)
+}
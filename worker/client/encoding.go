@@ -0,0 +1,87 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Payload Encoding (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// TS may gzip large payloads and send them base64-encoded.
+// The ticket's payloadHash always covers the decoded payload.
+// In the other direction, large result bodies may be gzipped on the wire
+// (COMPRESS_RESULTS); the signature covers the uncompressed signable data.
+
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithResultCompression gzips result post bodies larger than minBytes and
+// sends them with Content-Encoding: gzip (0 = off).
+func WithResultCompression(minBytes int) Option {
+	return func(c *APIClient) {
+		c.compressMin = minBytes
+	}
+}
+
+// compressBody gzips a result body when compression is enabled and the body
+// is large enough, setting Content-Encoding on header. Otherwise (or if
+// compression fails) body is returned unchanged.
+func (c *APIClient) compressBody(body []byte, header http.Header) []byte {
+	if c.compressMin <= 0 || len(body) <= c.compressMin {
+		return body
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return body
+	}
+	if err := zw.Close(); err != nil {
+		return body
+	}
+	header.Set("Content-Encoding", "gzip")
+	return buf.Bytes()
+}
+
+// Supported JobEnvelope.Encoding values.
+const (
+	EncodingNone       = ""
+	EncodingGzipBase64 = "gzip+base64"
+)
+
+// ErrPayloadTooLarge is returned by DecodePayload when a payload
+// decompresses to more than the limit.
+var ErrPayloadTooLarge = errors.New("decompressed payload too large")
+
+// DecodePayload returns the payload as TS hashed it, decompressing
+// gzip+base64 payloads up to maxBytes. Unencoded payloads are returned
+// unchanged.
+func (e *JobEnvelope) DecodePayload(maxBytes int) (string, error) {
+	switch e.Encoding {
+	case EncodingNone:
+		return e.Payload, nil
+	case EncodingGzipBase64:
+		raw, err := base64.StdEncoding.DecodeString(e.Payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to base64-decode payload: %w", err)
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return "", fmt.Errorf("failed to open gzip payload: %w", err)
+		}
+		defer zr.Close()
+		decoded, err := io.ReadAll(io.LimitReader(zr, int64(maxBytes)+1))
+		if err != nil {
+			return "", fmt.Errorf("failed to decompress payload: %w", err)
+		}
+		if len(decoded) > maxBytes {
+			return "", fmt.Errorf("%w: limit is %d bytes", ErrPayloadTooLarge, maxBytes)
+		}
+		return string(decoded), nil
+	default:
+		return "", fmt.Errorf("unsupported payload encoding: %s", e.Encoding)
+	}
+}
@@ -0,0 +1,66 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Payload Encryption (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// TS may encrypt payloads that carry secrets so they never sit in the
+// queue as plaintext. The encrypted payload is base64(nonce || ciphertext)
+// sealed with AES-256-GCM; decryption yields the payload exactly as it
+// would have been sent unencrypted (Encoding still applies afterwards).
+
+package client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+)
+
+// Supported JobEnvelope.Encryption values.
+const (
+	EncryptionNone   = ""
+	EncryptionAESGCM = "aes-256-gcm"
+)
+
+// NewPayloadCipher returns an AES-256-GCM AEAD for decrypting payloads.
+func NewPayloadCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("payload encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// DecryptPayload replaces an encrypted payload with its plaintext and clears
+// the Encryption marker. Unencrypted envelopes are left unchanged; aead may
+// be nil when no key is configured.
+func (e *JobEnvelope) DecryptPayload(aead cipher.AEAD) error {
+	switch e.Encryption {
+	case EncryptionNone:
+		return nil
+	case EncryptionAESGCM:
+		if aead == nil {
+			return fmt.Errorf("payload is encrypted but PAYLOAD_ENCRYPTION_KEY is not set")
+		}
+		raw, err := base64.StdEncoding.DecodeString(e.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to base64-decode encrypted payload: %w", err)
+		}
+		if len(raw) < aead.NonceSize() {
+			return fmt.Errorf("encrypted payload is shorter than the nonce")
+		}
+		nonce, sealed := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+		plain, err := aead.Open(nil, nonce, sealed, nil)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt payload: %w", err)
+		}
+		e.Payload = string(plain)
+		e.Encryption = EncryptionNone
+		return nil
+	default:
+		return fmt.Errorf("unsupported payload encryption: %s", e.Encryption)
+	}
+}
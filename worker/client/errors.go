@@ -0,0 +1,118 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — API Client Errors (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// APIError is returned for any non-success HTTP status from TS, so callers
+// can tell a 404 from a 503 (errors.As) and from a network failure (which
+// is returned as the underlying transport error). On 429/503 it carries
+// the Retry-After delay TS asked for; on an authentication
+// failure (see authFailure) it matches ErrAuthFailed.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBodyBytes bounds how much of an error response body is kept.
+const maxErrorBodyBytes = 4 << 10
+
+// APIError describes a TS response with an error status.
+type APIError struct {
+	StatusCode int
+	Endpoint   string
+	Body       string
+
+	// RetryAfter is the parsed Retry-After header (0 if absent)
+	RetryAfter time.Duration
+
+	// ContractMismatch is set when TS rejected our ContractVersion
+	ContractMismatch bool
+
+	// AuthFailed is set when TS rejected the worker's credentials (see authFailure)
+	AuthFailed bool
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	if e.ContractMismatch {
+		return fmt.Sprintf("%s failed (status %d): %v (worker speaks %s): %s",
+			e.Endpoint, e.StatusCode, ErrContractIncompatible, ContractVersion, e.Body)
+	}
+	if e.AuthFailed {
+		return fmt.Sprintf("%s failed (status %d): %v: %s", e.Endpoint, e.StatusCode, ErrAuthFailed, e.Body)
+	}
+	return fmt.Sprintf("%s failed (status %d): %s", e.Endpoint, e.StatusCode, e.Body)
+}
+
+// Unwrap exposes ErrContractIncompatible for version mismatch responses
+// and ErrAuthFailed for authentication failures.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.ContractMismatch:
+		return ErrContractIncompatible
+	case e.AuthFailed:
+		return ErrAuthFailed
+	}
+	return nil
+}
+
+// Retryable reports whether the failure is on the TS side (5xx) or a
+// rate limit (429); other 4xx responses will not succeed on retry.
+func (e *APIError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// newAPIError reads (a bounded prefix of) the response body into an APIError.
+func newAPIError(endpoint string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return &APIError{
+		StatusCode:       resp.StatusCode,
+		Endpoint:         endpoint,
+		Body:             string(body),
+		RetryAfter:       parseRetryAfter(resp.Header.Get("Retry-After")),
+		ContractMismatch: contractMismatch(resp),
+		AuthFailed:       authFailure(endpoint, resp),
+	}
+}
+
+// ResultHashMismatchCode is the error code TS puts in a result rejection
+// when its hash of resultData differs from the signed resultHash.
+const ResultHashMismatchCode = "RESULT_HASH_MISMATCH"
+
+// ResultHashMismatch reports whether err is TS rejecting a result post
+// because the result hash didn't match.
+func ResultHashMismatch(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && !apiErr.Retryable() && strings.Contains(apiErr.Body, ResultHashMismatchCode)
+}
+
+// RetryAfter returns the delay TS requested via Retry-After, if err is an
+// APIError carrying one.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After value in seconds or as an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(sec)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
@@ -0,0 +1,162 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — API Client Failover (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// COREOS_API_URL may list several TS endpoints (active/standby). On a
+// connection failure the next endpoint is tried and remembered as the
+// active one; while on a standby, the primary is re-probed periodically
+// and traffic returns to it once it answers again.
+//
+// Result posts and heartbeats can instead go to their own base URLs
+// (COREOS_RESULT_URL, COREOS_HEARTBEAT_URL) for deployments that split
+// reads from writes; those requests use only that URL, without failover.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+)
+
+// primaryRecheckInterval is how often the primary is re-probed while a
+// standby is active.
+const primaryRecheckInterval = 30 * time.Second
+
+// errInvalidRequest marks a request that could not be built (not retried).
+var errInvalidRequest = errors.New("invalid request")
+
+// WithFailoverURLs adds standby TS endpoints, tried in order after the
+// primary base URL.
+func WithFailoverURLs(urls ...string) Option {
+	return func(c *APIClient) {
+		c.endpoints = append(c.endpoints, urls...)
+	}
+}
+
+// WithOperationURLs sends result posts (result, result-stream) to
+// resultURL and heartbeats to heartbeatURL instead of the claim endpoints.
+// Empty = use the claim endpoints, with failover.
+func WithOperationURLs(resultURL, heartbeatURL string) Option {
+	return func(c *APIClient) {
+		c.resultURL = resultURL
+		c.heartbeatURL = heartbeatURL
+	}
+}
+
+// operationURL returns the dedicated base URL for path, or "" if path
+// goes to the claim endpoints.
+func (c *APIClient) operationURL(path string) string {
+	switch path {
+	case "/api/jobs/result", "/api/jobs/result-stream":
+		return c.resultURL
+	case "/api/jobs/heartbeat":
+		return c.heartbeatURL
+	}
+	return ""
+}
+
+// activeURL returns the endpoint requests currently go to.
+func (c *APIClient) activeURL() string {
+	return c.endpoints[c.active.Load()]
+}
+
+// send performs one request with hc, failing over to the next endpoint on
+// connection errors. A successful endpoint becomes the active one.
+func (c *APIClient) send(ctx context.Context, hc *http.Client, method, path string, body []byte, header http.Header) (*http.Response, error) {
+	if base := c.operationURL(path); base != "" {
+		return c.sendTo(ctx, hc, base, method, path, body, header)
+	}
+	c.maybeRestorePrimary(ctx)
+
+	n := len(c.endpoints)
+	start := int(c.active.Load())
+	var lastErr error
+	for i := 0; i < n; i++ {
+		idx := (start + i) % n
+		resp, err := c.sendTo(ctx, hc, c.endpoints[idx], method, path, body, header)
+		if errors.Is(err, errInvalidRequest) {
+			return nil, err
+		}
+		if err == nil {
+			if idx != start && c.active.CompareAndSwap(int32(start), int32(idx)) {
+				c.logger.Warn("failed over to TS endpoint", "endpoint", c.endpoints[idx])
+			}
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+		if n > 1 {
+			c.logger.Warn("TS endpoint unreachable", "endpoint", c.endpoints[idx], logging.KeyError, err)
+		}
+	}
+	return nil, lastErr
+}
+
+// sendTo performs one request against baseURL, recording its metrics and
+// breaker outcome.
+func (c *APIClient) sendTo(ctx context.Context, hc *http.Client, baseURL, method, path string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := c.newRequestTo(ctx, baseURL, method, path, body, header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidRequest, err)
+	}
+
+	sentAt := time.Now()
+	resp, err := hc.Do(req)
+	c.recordRequest(path, resp, sentAt)
+	if ctx.Err() == nil {
+		c.recordOutcome(resp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.checkContract(path, resp)
+	c.checkAuth(path, resp)
+	return resp, nil
+}
+
+// recordRequest records one request attempt; resp is nil on transport errors.
+func (c *APIClient) recordRequest(path string, resp *http.Response, sentAt time.Time) {
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	c.metrics.IncrCounter(MetricRequests, metrics.Labels{"path": path, "status": status})
+	c.metrics.ObserveHistogram(MetricRequestLatency, float64(time.Since(sentAt).Milliseconds()), metrics.Labels{"path": path})
+}
+
+// maybeRestorePrimary re-probes the primary while a standby is active
+// (at most once per primaryRecheckInterval) and switches back if it answers.
+func (c *APIClient) maybeRestorePrimary(ctx context.Context) {
+	if c.active.Load() == 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := c.primaryChecked.Load()
+	if now-last < int64(primaryRecheckInterval) || !c.primaryChecked.CompareAndSwap(last, now) {
+		return
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := c.newRequestTo(probeCtx, c.endpoints[0], http.MethodHead, "", nil, nil)
+	if err != nil {
+		return
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+
+	c.active.Store(0)
+	c.logger.Info("primary TS endpoint reachable again, failing back", "endpoint", c.endpoints[0])
+}
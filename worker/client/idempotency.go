@@ -0,0 +1,97 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Result Idempotency (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Every result post carries Idempotency-Key: <jobId>:<attempt> so TS can
+// drop a retried post whose first attempt committed despite timing out.
+// Locally, a result for the same job attempt is never posted twice while
+// its key is in a bounded LRU (RESULT_DEDUP_CACHE_SIZE); a suppressed
+// duplicate is logged.
+
+package client
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// ErrResultAlreadyPosted is returned when this worker has already posted
+// (or is posting) a result for the same job attempt.
+var ErrResultAlreadyPosted = errors.New("result already posted for this job attempt")
+
+// DefaultResultDedupCacheSize bounds the local guard; duplicate posts
+// happen close together, and older keys are still deduped by TS.
+const DefaultResultDedupCacheSize = 10000
+
+// WithResultDedupCacheSize sets how many recently posted job attempts are
+// remembered to suppress duplicate posts (default DefaultResultDedupCacheSize).
+func WithResultDedupCacheSize(n int) Option {
+	return func(c *APIClient) {
+		if n > 0 {
+			c.posted = newPostedKeys(n)
+		}
+	}
+}
+
+// reserveResult reserves a result's idempotency key for posting, or logs
+// the duplicate and returns ErrResultAlreadyPosted.
+func (c *APIClient) reserveResult(result *contracts.JobResult, key string) error {
+	if c.posted.reserve(key) {
+		return nil
+	}
+	c.logger.Warn("duplicate result suppressed",
+		logging.KeyJobID, result.JobID,
+		logging.KeyAttempt, result.Metrics.Attempts,
+		logging.KeyStatus, result.Status)
+	return fmt.Errorf("%w: %s", ErrResultAlreadyPosted, key)
+}
+
+// idempotencyKey identifies one job attempt's result.
+func idempotencyKey(result *contracts.JobResult) string {
+	return fmt.Sprintf("%s:%d", result.JobID, result.Metrics.Attempts)
+}
+
+// postedKeys is a bounded LRU set of idempotency keys.
+type postedKeys struct {
+	mu    sync.Mutex
+	max   int
+	keys  map[string]*list.Element
+	order *list.List // most recently reserved or hit at the front
+}
+
+func newPostedKeys(max int) *postedKeys {
+	return &postedKeys{max: max, keys: make(map[string]*list.Element), order: list.New()}
+}
+
+// reserve claims key for posting; false if it is already reserved or posted.
+func (p *postedKeys) reserve(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e, ok := p.keys[key]; ok {
+		p.order.MoveToFront(e) // a key still being duplicated stays remembered
+		return false
+	}
+	if p.order.Len() >= p.max {
+		oldest := p.order.Back()
+		delete(p.keys, oldest.Value.(string))
+		p.order.Remove(oldest)
+	}
+	p.keys[key] = p.order.PushFront(key)
+	return true
+}
+
+// release drops a reservation after a failed post so it can be retried.
+func (p *postedKeys) release(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e, ok := p.keys[key]; ok {
+		delete(p.keys, key)
+		p.order.Remove(e)
+	}
+}
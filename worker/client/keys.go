@@ -0,0 +1,44 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Ticket Signing Keys (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// GET /api/keys lists the Ed25519 public keys TS currently signs tickets
+// with, so a key rotation doesn't need a worker redeploy. The response is
+// untrusted on its own: the worker only accepts keys matching its pinned
+// fingerprints (see worker/keys.go).
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// maxKeysBodyBytes bounds the /api/keys response read.
+const maxKeysBodyBytes = 64 << 10
+
+// KeysResponse is the /api/keys reply.
+type KeysResponse struct {
+	Keys []string `json:"keys"` // base64 Ed25519 public keys
+}
+
+// FetchPublicKeys returns the ticket signing keys TS advertises (base64).
+// It is a single attempt: callers fall back to the keys they already have.
+func (c *APIClient) FetchPublicKeys(ctx context.Context) ([]string, error) {
+	resp, err := c.send(ctx, c.httpClient, http.MethodGet, "/api/keys", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("keys request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError("/api/keys", resp)
+	}
+
+	var keys KeysResponse
+	if err := c.decodeLimited(resp.Body, maxKeysBodyBytes, &keys); err != nil {
+		return nil, fmt.Errorf("invalid keys response: %w", err)
+	}
+	return keys.Keys, nil
+}
@@ -0,0 +1,65 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Long-Poll Claim (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// CLAIM_MODE=longpoll: TS holds the claim open until a job is available or
+// the wait hint elapses, cutting both pickup latency and idle requests.
+// Each call is one attempt (with endpoint failover); the worker loop owns
+// reconnect backoff.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrLongPollUnsupported is returned by ClaimJobLongPoll when TS has no
+// long-poll endpoint (404/501), so callers can fall back to ticker polling.
+var ErrLongPollUnsupported = errors.New("long-poll claim not supported by TS")
+
+// ClaimJobLongPoll calls POST /api/jobs/claim-longpoll, asking TS to wait up
+// to waitSeconds for a job. The request timeout is the client timeout plus
+// the wait. Returns nil if the wait elapsed with no job.
+func (c *APIClient) ClaimJobLongPoll(ctx context.Context, workerID string, waitSeconds int) (*JobEnvelope, error) {
+	const path = "/api/jobs/claim-longpoll"
+	if err := c.allowClaim(); err != nil {
+		return nil, err
+	}
+
+	reqBody := c.newWaitClaimRequest(workerID, 0, waitSeconds)
+
+	hc := &http.Client{
+		Timeout:   c.httpClient.Timeout + time.Duration(waitSeconds)*time.Second,
+		Transport: c.transport,
+	}
+	resp, err := c.send(ctx, hc, http.MethodPost, path, reqBody, nil)
+	if err != nil {
+		return nil, fmt.Errorf("long-poll claim request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return nil, ErrLongPollUnsupported
+	}
+
+	if resp.StatusCode == 204 {
+		c.recordClaim(path, resp.StatusCode, 0)
+		return nil, nil
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(path, resp)
+	}
+
+	var pollResp PollResponse
+	if err := c.decodeLimited(resp.Body, c.maxJobBytes, &pollResp); err != nil {
+		return nil, fmt.Errorf("failed to decode long-poll claim response: %w", err)
+	}
+	c.recordClaim(path, resp.StatusCode, countJobs(pollResp.Job))
+
+	return c.claimedEnvelope(pollResp.Job), nil
+}
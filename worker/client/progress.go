@@ -0,0 +1,46 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Progress Checkpoints (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Long-running handlers checkpoint their progress; the worker posts the
+// latest checkpoint alongside heartbeats so TS can hand it back in the
+// payload of a retry. Checkpoints are best-effort: a single attempt with
+// endpoint failover, never retried.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrProgressUnsupported is returned by PostProgress when TS has no
+// progress endpoint (404/501).
+var ErrProgressUnsupported = errors.New("progress checkpoints not supported by TS")
+
+// PostProgress calls POST /api/jobs/progress with a job's latest checkpoint.
+func (c *APIClient) PostProgress(ctx context.Context, jobID, workerID, traceID string, checkpoint json.RawMessage) error {
+	const path = "/api/jobs/progress"
+	reqBody, _ := json.Marshal(map[string]any{
+		"jobId":      jobID,
+		"workerId":   workerID,
+		"checkpoint": checkpoint,
+	})
+
+	resp, err := c.send(ctx, c.httpClient, http.MethodPost, path, reqBody, c.traceHeaders(traceID))
+	if err != nil {
+		return fmt.Errorf("progress request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return ErrProgressUnsupported
+	}
+	if resp.StatusCode >= 400 {
+		return newAPIError(path, resp)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — API Client Retry (Phase 22A)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Exponential backoff with jitter for transient TS failures.
+// Retries connection errors, 5xx and 429 responses (honoring Retry-After);
+// other 4xx are returned as-is.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// maxRetryDelay caps a single backoff sleep.
+const maxRetryDelay = 30 * time.Second
+
+// RetryPolicy controls how transient HTTP failures are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt (0 = no retry).
+	MaxRetries int
+
+	// BaseDelay is the backoff delay before the first retry; it doubles per retry.
+	BaseDelay time.Duration
+}
+
+// backoff returns the delay before retry number attempt (0-based),
+// using exponential growth with equal jitter: [d/2, d). Doubling stops at
+// maxRetryDelay, so a large attempt can't overflow.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 0; i < attempt && d > 0 && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// doWithRetry POSTs a JSON body (plus any extra headers) to path,
+// retrying connection errors, 5xx and 429 responses. The final response (or error) is returned to the caller,
+// who owns the response body. Cancelling ctx aborts the in-flight request
+// and interrupts a retry sleep.
+func (c *APIClient) doWithRetry(ctx context.Context, path string, body []byte, header http.Header) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, c.httpClient, http.MethodPost, path, body, header)
+		if errors.Is(err, errInvalidRequest) {
+			return nil, err
+		}
+		retryable := err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		if !retryable || attempt >= c.retry.MaxRetries || (claimPath(path) && c.breakerOpen()) {
+			return resp, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			// TS asked us to slow down: wait at least as long as it said
+			if wait := parseRetryAfter(resp.Header.Get("Retry-After")); wait > delay {
+				delay = min(wait, maxRetryDelay)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		c.logger.Warn("request failed, retrying",
+			"path", path,
+			"try", attempt+1,
+			"maxTries", c.retry.MaxRetries+1,
+			"reason", reason,
+			"delay", delay.String())
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Chunked Result Upload (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Large result data is uploaded to /api/jobs/result-stream in chunks, then
+// finalized with the signed result metadata. ResultHash always covers the
+// full concatenated bytes, so TS verifies it exactly as for a single-shot
+// post once the chunks are reassembled.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+)
+
+// ResultChunkSize is the size of each uploaded result chunk.
+const ResultChunkSize = 256 << 10
+
+// ErrStreamUnsupported is returned when TS has no result-stream endpoint;
+// callers should fall back to PostResult.
+var ErrStreamUnsupported = errors.New("result-stream endpoint not supported by TS")
+
+// resultChunk is one piece of the result data ([]byte is sent as base64).
+type resultChunk struct {
+	JobID string `json:"jobId"`
+	Seq   int    `json:"seq"`
+	Data  []byte `json:"data"`
+}
+
+// resultStreamFinalize completes an upload. Result carries no ResultData;
+// TS uses the reassembled chunks instead.
+type resultStreamFinalize struct {
+	Final  bool                 `json:"final"`
+	Chunks int                  `json:"chunks"`
+	Result *contracts.JobResult `json:"result"`
+}
+
+// PostResultStream uploads data (the JSON-encoded result data) in chunks
+// and then posts the signed result. result.ResultData must be nil and
+// result.ResultHash must be the SHA-256 of data. Like PostResult it returns
+// TS's disposition of the result.
+// Returns ErrStreamUnsupported if TS answers the first chunk with 404.
+func (c *APIClient) PostResultStream(ctx context.Context, result *contracts.JobResult, data []byte) (string, error) {
+	key := idempotencyKey(result)
+	if err := c.reserveResult(result, key); err != nil {
+		return "", err
+	}
+	header := c.resultHeaders(result, key)
+
+	disposition, err := c.streamResult(ctx, result, data, header)
+	if err != nil {
+		c.posted.release(key)
+		return "", err
+	}
+	return disposition, nil
+}
+
+func (c *APIClient) streamResult(ctx context.Context, result *contracts.JobResult, data []byte, header http.Header) (string, error) {
+	chunks := 0
+	for off := 0; off < len(data); off += ResultChunkSize {
+		end := min(off+ResultChunkSize, len(data))
+		body, _ := json.Marshal(resultChunk{JobID: result.JobID, Seq: chunks, Data: data[off:end]})
+
+		if err := c.postChunk(ctx, body, header, chunks); err != nil {
+			return "", err
+		}
+		chunks++
+	}
+
+	body, err := json.Marshal(resultStreamFinalize{Final: true, Chunks: chunks, Result: result})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return c.sendResult(ctx, result, "/api/jobs/result-stream", body, header)
+}
+
+// postChunk uploads a single chunk.
+func (c *APIClient) postChunk(ctx context.Context, body []byte, header http.Header, seq int) error {
+	resp, err := c.doWithRetry(ctx, "/api/jobs/result-stream", body, header)
+	if err != nil {
+		return fmt.Errorf("failed to post result chunk %d: %w", seq, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 && seq == 0 {
+		return ErrStreamUnsupported
+	}
+	if resp.StatusCode >= 400 {
+		return newAPIError("/api/jobs/result-stream", resp)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — API Client TLS (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Client certificate (mTLS) and custom CA support for TS ingress.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// LoadTLSConfig builds a tls.Config from PEM files on disk.
+// certFile/keyFile enable client authentication (both or neither);
+// caFile replaces the system roots used to verify TS (optional).
+func LoadTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid PEM certificates found in %s", caFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	return tlsCfg, nil
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to TS.
+func WithTLSConfig(tlsCfg *tls.Config) Option {
+	return func(c *APIClient) {
+		c.transport.TLSClientConfig = tlsCfg
+	}
+}
@@ -0,0 +1,37 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — API Client Trace Propagation (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Propagates the job traceId to TS as X-Trace-Id, and optionally as a
+// W3C traceparent header (TRACE_W3C=true).
+
+package client
+
+import (
+	"net/http"
+
+	"github.com/gemimi2525-star/super-platform/worker/tracing"
+)
+
+// HeaderTraceID carries the Core OS traceId on outbound requests.
+const HeaderTraceID = "X-Trace-Id"
+
+// traceHeaders returns the trace headers for a job-scoped request.
+func (c *APIClient) traceHeaders(traceID string) http.Header {
+	h := http.Header{}
+	if traceID == "" {
+		return h
+	}
+	h.Set(HeaderTraceID, traceID)
+	if c.w3cTrace {
+		h.Set("traceparent", traceParent(traceID))
+	}
+	return h
+}
+
+// traceParent builds a W3C traceparent (version 00, sampled) for traceID.
+// A 32-hex traceID is used as-is; anything else is hashed to 16 bytes so
+// the same Core OS traceId always maps to the same W3C trace-id.
+func traceParent(traceID string) string {
+	return "00-" + tracing.W3CTraceID(traceID) + "-" + tracing.NewSpanID() + "-01"
+}
@@ -0,0 +1,227 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Claim Envelope Validation (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Claimed envelopes are checked field by field before they are decoded, so
+// contract drift (a missing field, "attempts" sent as a string) produces an
+// EnvelopeError naming every offending field path and the expected type
+// instead of a bare json error. A malformed envelope is logged and skipped;
+// the rest of a batch is still returned.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// FieldError is one envelope field that is missing or has the wrong type.
+type FieldError struct {
+	Path     string `json:"path"`     // e.g. "ticket.expiresAt"
+	Expected string `json:"expected"` // "string", "integer", "string array" or "object"
+	Got      string `json:"got"`      // JSON type found, or "missing"
+}
+
+// EnvelopeError lists every invalid field of a claimed envelope.
+type EnvelopeError struct {
+	JobID  string // "" if the jobId itself is unusable
+	Fields []FieldError
+}
+
+// Error implements error.
+func (e *EnvelopeError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = fmt.Sprintf("%s: want %s, got %s", f.Path, f.Expected, f.Got)
+	}
+	jobID := e.JobID
+	if jobID == "" {
+		jobID = "unknown"
+	}
+	return fmt.Sprintf("malformed envelope (jobId %s): %s", jobID, strings.Join(parts, "; "))
+}
+
+// Expected field types.
+const (
+	kindString     = "string"
+	kindInteger    = "integer"
+	kindStringList = "string array"
+	kindObject     = "object"
+)
+
+// fieldSpec describes one envelope or ticket field.
+type fieldSpec struct {
+	name     string
+	kind     string
+	required bool
+}
+
+// envelopeFields mirrors JobEnvelope.
+var envelopeFields = []fieldSpec{
+	{"ticket", kindObject, true},
+	{"payload", kindString, false},
+	{"encoding", kindString, false},
+	{"encryption", kindString, false},
+	{"version", kindString, false},
+	{"attempts", kindInteger, false},
+	{"maxAttempts", kindInteger, false},
+	{"priority", kindInteger, false},
+	{"traceparent", kindString, false},
+	{"leaseDurationMs", kindInteger, false},
+	{"handlerVersion", kindString, false},
+	{"affinityKey", kindString, false},
+}
+
+// ticketFields mirrors contracts.JobTicket; required fields are the ones
+// no ticket check can pass without.
+var ticketFields = []fieldSpec{
+	{"jobId", kindString, true},
+	{"jobType", kindString, true},
+	{"actorId", kindString, false},
+	{"scope", kindStringList, false},
+	{"policyDecisionId", kindString, false},
+	{"requestedAt", kindInteger, false},
+	{"expiresAt", kindInteger, true},
+	{"payloadHash", kindString, true},
+	{"nonce", kindString, true},
+	{"traceId", kindString, true},
+	{"signature", kindString, true},
+}
+
+// decodeEnvelopes validates and decodes claimed envelopes, logging and
+// dropping malformed ones. Null entries (no job) are skipped.
+func (c *APIClient) decodeEnvelopes(raws ...json.RawMessage) []JobEnvelope {
+	envelopes := make([]JobEnvelope, 0, len(raws))
+	for _, raw := range raws {
+		if isNull(raw) {
+			continue
+		}
+		envelope, err := decodeEnvelope(raw)
+		if err != nil {
+			var envErr *EnvelopeError
+			if errors.As(err, &envErr) {
+				c.logger.Warn("skipping malformed envelope",
+					logging.KeyJobID, envErr.JobID,
+					"fields", envErr.Fields,
+					logging.KeyError, err)
+			} else {
+				c.logger.Warn("skipping malformed envelope", logging.KeyError, err)
+			}
+			continue
+		}
+		envelopes = append(envelopes, *envelope)
+	}
+	return envelopes
+}
+
+// claimedEnvelope is decodeEnvelopes for a single-job response: nil if
+// there is no job or it is malformed. A skipped envelope's lease simply
+// expires on TS, which counts the attempt against maxAttempts.
+func (c *APIClient) claimedEnvelope(raw json.RawMessage) *JobEnvelope {
+	if envelopes := c.decodeEnvelopes(raw); len(envelopes) == 1 {
+		return &envelopes[0]
+	}
+	return nil
+}
+
+// decodeEnvelope checks raw against the envelope contract, then decodes it.
+func decodeEnvelope(raw json.RawMessage) (*JobEnvelope, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, &EnvelopeError{Fields: []FieldError{{Path: "(envelope)", Expected: kindObject, Got: jsonType(raw)}}}
+	}
+
+	problems := checkFields("", fields, envelopeFields)
+	var jobID string
+	var ticket map[string]json.RawMessage
+	if json.Unmarshal(fields["ticket"], &ticket) == nil && ticket != nil {
+		problems = append(problems, checkFields("ticket.", ticket, ticketFields)...)
+		json.Unmarshal(ticket["jobId"], &jobID)
+	}
+	if len(problems) > 0 {
+		return nil, &EnvelopeError{JobID: jobID, Fields: problems}
+	}
+
+	var envelope JobEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("decode envelope (jobId %s): %w", jobID, err)
+	}
+	return &envelope, nil
+}
+
+// checkFields validates fields against specs; paths are prefixed.
+func checkFields(prefix string, fields map[string]json.RawMessage, specs []fieldSpec) []FieldError {
+	var problems []FieldError
+	for _, spec := range specs {
+		path := prefix + spec.name
+		value, ok := fields[spec.name]
+		if !ok || isNull(value) {
+			if spec.required {
+				problems = append(problems, FieldError{Path: path, Expected: spec.kind, Got: "missing"})
+			}
+			continue
+		}
+		if got, ok := matchKind(value, spec.kind); !ok {
+			problems = append(problems, FieldError{Path: path, Expected: spec.kind, Got: got})
+		}
+	}
+	return problems
+}
+
+// matchKind reports whether value has kind, and otherwise what it has.
+func matchKind(value json.RawMessage, kind string) (string, bool) {
+	got := jsonType(value)
+	switch kind {
+	case kindInteger:
+		var n int64
+		if got == "number" && json.Unmarshal(value, &n) != nil {
+			return "non-integer number", false
+		}
+		return got, got == "number"
+	case kindStringList:
+		var items []json.RawMessage
+		if got != "array" || json.Unmarshal(value, &items) != nil {
+			return got, false
+		}
+		for _, item := range items {
+			if t := jsonType(item); t != "string" {
+				return "array containing " + t, false
+			}
+		}
+		return got, true
+	default:
+		return got, got == kind
+	}
+}
+
+// jsonType names the JSON type of a raw value.
+func jsonType(raw json.RawMessage) string {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return "empty"
+	}
+	switch trimmed[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+func isNull(raw json.RawMessage) bool {
+	t := jsonType(raw)
+	return t == "null" || t == "empty"
+}
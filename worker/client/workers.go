@@ -0,0 +1,76 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Worker Registration (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Startup/shutdown handshake so TS can keep a live worker inventory and
+// route jobs by capability (the jobTypes a worker can run). TS answers 409
+// when the worker ID is already active, so two pods sharing a WORKER_ID
+// are caught at startup.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrWorkerIDConflict is returned by Register when TS reports the worker ID
+// as already active.
+var ErrWorkerIDConflict = errors.New("worker ID already active on TS")
+
+// ErrRegisterUnsupported is returned by Register when TS has no
+// registration endpoint (404).
+var ErrRegisterUnsupported = errors.New("worker registration not supported by TS")
+
+// Register calls POST /api/workers/register with this worker's capabilities.
+func (c *APIClient) Register(ctx context.Context, workerID string, capabilities []string) error {
+	reqBody, _ := json.Marshal(map[string]any{
+		"workerId":     workerID,
+		"capabilities": capabilities,
+	})
+
+	resp, err := c.doWithRetry(ctx, "/api/workers/register", reqBody, nil)
+	if err != nil {
+		return fmt.Errorf("register request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrRegisterUnsupported
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := newAPIError("/api/workers/register", resp)
+		if resp.StatusCode == http.StatusConflict && !apiErr.ContractMismatch {
+			return fmt.Errorf("%w: %v", ErrWorkerIDConflict, apiErr)
+		}
+		return apiErr
+	}
+	return nil
+}
+
+// SetWorkerID changes the ID sent in X-Worker-Id (if enabled). Not safe
+// to call while requests are in flight.
+func (c *APIClient) SetWorkerID(workerID string) {
+	if c.workerID != "" {
+		c.workerID = workerID
+	}
+}
+
+// Deregister calls POST /api/workers/deregister when the worker shuts down.
+func (c *APIClient) Deregister(ctx context.Context, workerID string) error {
+	reqBody, _ := json.Marshal(map[string]string{"workerId": workerID})
+
+	resp, err := c.doWithRetry(ctx, "/api/workers/deregister", reqBody, nil)
+	if err != nil {
+		return fmt.Errorf("deregister request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return newAPIError("/api/workers/deregister", resp)
+	}
+	return nil
+}
@@ -5,72 +5,905 @@
 package config
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log/slog"
+	"math"
+	"net/textproto"
+	"net/url"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/logging"
 )
 
 // Config holds all worker configuration from environment variables.
 type Config struct {
-	// API endpoint for Core OS (TS)
-	APIURL string
+	// API endpoint for Core OS (TS), plus standbys tried in order on failure
+	APIURL         string
+	APIStandbyURLs []string
+
+	// Base URLs for result posts and heartbeats ("" = the claim endpoints)
+	ResultURL    string
+	HeartbeatURL string
 
 	// HMAC shared secret for signing results
 	HMACSecret string
 
+	// Bearer token sent on every TS request (optional)
+	AuthToken string
+
+	// Send X-Worker-Id on every TS request
+	WorkerIDHeader bool
+
+	// Static headers sent on every TS request (e.g. ingress routing tags);
+	// headers the client sets itself take precedence
+	ExtraHTTPHeaders map[string]string
+
 	// Ed25519 public key (base64) for verifying tickets
 	PublicKeyBase64 string
 
+	// Also verify against signing keys fetched from TS /api/keys, cached
+	// for TicketKeysTTL; only keys whose SHA-256 (hex) equals a pinned
+	// fingerprint are accepted
+	TicketKeysFetch       bool
+	TicketKeysTTL         time.Duration
+	TicketKeyFingerprints []string
+
+	// AES-256 key (base64) for decrypting encrypted payloads (optional)
+	PayloadEncryptionKey string
+
+	// mTLS: client certificate/key and CA bundle (PEM files, optional)
+	ClientCertFile string
+	ClientKeyFile  string
+	CACertFile     string
+
+	// Minimum TLS version and (TLS 1.2) cipher suite allow-list (nil = Go defaults)
+	TLSMinVersion   uint16
+	TLSCipherSuites []uint16
+
 	// Worker instance identifier
 	WorkerID string
 
+	// WorkerID was generated (WORKER_ID unset), so it may be suffixed when
+	// TS can't check uniqueness
+	WorkerIDGenerated bool
+
+	// Keep running when TS reports WorkerID as already active
+	AllowDuplicateWorkerID bool
+
+	// Shut down when a claim fails authentication (401/403), so the
+	// orchestrator restarts the worker with fresh credentials
+	ExitOnAuthFailure bool
+
 	// Queue polling interval
 	PollInterval time.Duration
 
+	// Claim loop: "poll" (ticker) or "longpoll" (TS holds each single-job claim
+	// open up to LongPollWait; CLAIM_BATCH_SIZE does not apply)
+	ClaimMode    string
+	LongPollWait time.Duration
+
+	// Delay the first poll by a random fraction of PollInterval (fleet restarts)
+	PollJitter bool
+
+	// Upper bound for the poll interval while the queue stays empty
+	MaxPollInterval time.Duration
+
+	// Consecutive failed claims after which /healthz reports unhealthy
+	ClaimFailureThreshold int
+
+	// After startup, until this passes or the first claim succeeds, /readyz
+	// reports "starting" and the claim-failure threshold is not applied
+	// (0 = no grace)
+	StartupGrace time.Duration
+
+	// Consecutive failed TS requests that open the circuit breaker (0 = off),
+	// and how long claims are skipped before a probe
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+
+	// Longest claim pause honoured from a TS Retry-After
+	ClaimRetryAfterMax time.Duration
+
+	// Only claim jobs with priority >= this (TS's enqueue priority, passed
+	// through unbounded; 0 = all)
+	ClaimMinPriority int
+
+	// Ask TS to route jobs by affinityKey to this worker (best-effort hint)
+	ClaimAffinity bool
+
+	// Send "busy": true on the first claim after the pool was saturated,
+	// so TS can deprioritize this worker
+	ClaimBusyHint bool
+
+	// jobType allow/deny lists and per-jobType caps (see routing.go)
+	Routing
+
+	// Lease length requested with each claim (0 = TS default), with
+	// per-jobType overrides in seconds
+	VisibilityTimeout        time.Duration
+	JobTypeVisibilityTimeout map[string]int
+
+	// Claim rate limit in jobs/second (0 = unlimited) and bucket size
+	MaxJobsPerSecond float64
+	ClaimBurst       int
+
+	// Maximum jobs executing concurrently in this worker
+	MaxConcurrency int
+
+	// Size MaxConcurrency as NumCPU × multiplier; with a latency SLO the
+	// effective limit backs off (AIMD) while jobs run slower than it
+	AutoConcurrency           bool
+	AutoConcurrencyMultiplier float64
+	ConcurrencyLatencySLO     time.Duration
+
+	// Maximum jobs requested per claim round-trip (1 = single claim)
+	ClaimBatchSize int
+
+	// Claimed jobs waiting in the worker-local queue for a free executor
+	// (0 = no queue: claim only for free slots)
+	LocalQueueDepth int
+
 	// HTTP client timeout
 	HTTPTimeout time.Duration
+
+	// Connection pool tuning for the TS client (MaxConnsPerHost 0 = unlimited)
+	MaxIdleConns    int
+	MaxConnsPerHost int
+	IdleConnTimeout time.Duration
+
+	// Connect and TLS handshake limits for new TS connections, independent
+	// of HTTPTimeout so a dead host fails fast while long bodies still stream
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+
+	// How long shutdown waits for running jobs before releasing their leases
+	// (0 = don't wait)
+	ShutdownDrain time.Duration
+
+	// Overall shutdown deadline: the drain plus reporting abandoned jobs
+	ShutdownTimeout time.Duration
+
+	// Self-recycle: stop claiming and exit after this many jobs / this long (0 = never)
+	MaxJobsBeforeExit int
+	MaxLifetime       time.Duration
+
+	// Consecutive heartbeat failures after which the lease is considered lost (0 = never)
+	HeartbeatFailureThreshold int
+
+	// retryAfterMs suggested on retryable FAILED results: base * 2^attempt,
+	// capped at RetryBackoffMax (base 0 = off; a jobType's RetryPolicy wins)
+	RetryBackoffBase time.Duration
+	RetryBackoffMax  time.Duration
+
+	// Retries for transient HTTP failures (connection errors, 5xx)
+	HTTPMaxRetries int
+
+	// Base delay for exponential retry backoff
+	HTTPRetryBase time.Duration
+
+	// Port for /healthz and /readyz (0 = disabled)
+	HealthPort int
+
+	// Serve Prometheus /metrics on the health server
+	MetricsEnabled bool
+
+	// Serve /debug/jobs (in-flight jobs) and /debug/handlers on the health server
+	DebugEnabled bool
+
+	// Results whose encoded data exceeds this are uploaded in chunks
+	ResultStreamThreshold int
+
+	// Results whose encoded data exceeds this are replaced by a truncation
+	// summary before posting (0 = no cap)
+	MaxResultBytes int
+
+	// Capture handler log lines and attach their last MaxLogCaptureBytes to
+	// the result data under "logs", SUCCEEDED or FAILED (0 = off)
+	MaxLogCaptureBytes int
+
+	// Claim no new jobs while in-flight payload + result bytes are at or
+	// above this (0 = no cap)
+	MaxInflightBytes int64
+
+	// Keep retrying a failed result post for up to ResultPostMaxWait, with
+	// backoff capped at ResultPostBackoffMax; then spool it to ResultSpoolDir
+	// (if set) for delivery once TS is back
+	ResultPostMaxWait    time.Duration
+	ResultPostBackoffMax time.Duration
+	ResultSpoolDir       string
+
+	// Gzip result post bodies larger than CompressResultsMinBytes
+	CompressResults         bool
+	CompressResultsMinBytes int
+
+	// Recently posted (jobId, attempt) results remembered to suppress a
+	// duplicate post
+	ResultDedupCacheSize int
+
+	// Largest envelope payload (as sent, before decoding) the worker accepts
+	MaxPayloadBytes int
+
+	// Maximum number of ticket nonces kept for replay protection
+	NonceCacheSize int
+
+	// Minimum decoded size of a ticket nonce in bytes (0 = no check). TS
+	// nonces are randomUUID() strings, which count as 16 bytes
+	NonceMinBytes int
+
+	// Minimum log level (LOG_LEVEL)
+	LogLevel slog.Level
+
+	// Log output format: "text" or "json" (LOG_FORMAT)
+	LogFormat string
+
+	// Write 1 in N routine (below WARN) lines per message (1 = all)
+	LogSampleRate int
+
+	// Also send a W3C traceparent header on job-scoped requests
+	TraceW3C bool
+
+	// Export a span per job as OTLP/HTTP JSON to OTelEndpoint (OTEL_* env vars)
+	OTelEnabled     bool
+	OTelEndpoint    string
+	OTelServiceName string
+
+	// Validate tickets only: peek (no lease), skip dispatch and result posts
+	DryRun bool
+
+	// Tolerated clock difference with TS for ticket timestamps
+	ClockSkew time.Duration
+
+	// Result HMAC field set: 1 (TS signer fields) or 2 (also error and retry/truncation fields)
+	ResultSignatureVersion int
+
+	// Reject jobs requested longer ago than this (0 = no limit)
+	MaxJobAge time.Duration
+
+	// Require a signed ack (HMAC, shared secret) from the result endpoint
+	ExpectAck bool
+
+	// Record in-flight jobs under StateDir and report them INTERRUPTED on restart
+	CrashRecovery bool
+	StateDir      string
+
+	// Append every accepted result to a JSON-lines audit log under AuditDir
+	AuditEnabled bool
+	AuditDir     string
+
+	// Write every claimed envelope to a JSON file here (--replay input)
+	RecordEnvelopesDir string
+
+	// Register test-only handlers such as __test.echo (never in production)
+	EnableTestHandlers bool
+
+	// Let http.request jobs reach private, loopback and link-local addresses
+	AllowPrivateTargets bool
+
+	// Webhook notified when a job fails its terminal attempt (optional)
+	DeadLetterWebhookURL string
+
+	// File settings unset in the env were read from (WORKER_CONFIG_FILE)
+	ConfigFile string
+
+	// What SIGHUP does: "drain" toggles draining, "reload" re-reads Routing
+	// from ConfigFile
+	SighupAction string
 }
 
-// Load reads configuration from environment variables.
+// Load reads configuration from environment variables, falling back to
+// WORKER_CONFIG_FILE for any that are unset (see file.go).
 func Load() (*Config, error) {
-	apiURL := os.Getenv("COREOS_API_URL")
-	if apiURL == "" {
+	getenv, err := configSource()
+	if err != nil {
+		return nil, err
+	}
+
+	apiURLs := splitList(getenv("COREOS_API_URL"))
+	if len(apiURLs) == 0 {
 		return nil, fmt.Errorf("COREOS_API_URL is required")
 	}
+	for i, raw := range apiURLs {
+		if apiURLs[i], err = normalizeBaseURL(raw); err != nil {
+			return nil, fmt.Errorf("COREOS_API_URL: %w", err)
+		}
+	}
 
-	hmacSecret := os.Getenv("JOB_WORKER_HMAC_SECRET")
+	// Result posts and heartbeats default to the claim endpoints
+	resultURL, err := normalizeBaseURL(strings.TrimSpace(getenv("COREOS_RESULT_URL")))
+	if err != nil {
+		return nil, fmt.Errorf("COREOS_RESULT_URL: %w", err)
+	}
+	heartbeatURL, err := normalizeBaseURL(strings.TrimSpace(getenv("COREOS_HEARTBEAT_URL")))
+	if err != nil {
+		return nil, fmt.Errorf("COREOS_HEARTBEAT_URL: %w", err)
+	}
+
+	hmacSecret := getenv("JOB_WORKER_HMAC_SECRET")
 	if hmacSecret == "" {
 		return nil, fmt.Errorf("JOB_WORKER_HMAC_SECRET is required")
 	}
 
-	publicKey := os.Getenv("JOB_TICKET_PUBLIC_KEY")
+	publicKey := getenv("JOB_TICKET_PUBLIC_KEY")
 	if publicKey == "" {
 		return nil, fmt.Errorf("JOB_TICKET_PUBLIC_KEY is required (base64 Ed25519 public key)")
 	}
 
-	workerID := os.Getenv("WORKER_ID")
-	if workerID == "" {
+	ticketKeysFetch := getenv("TICKET_KEYS_FETCH") == "true"
+	ticketKeyFingerprints, err := parseKeyFingerprints(splitList(getenv("TICKET_KEY_FINGERPRINTS")))
+	if err != nil {
+		return nil, fmt.Errorf("TICKET_KEY_FINGERPRINTS: %w", err)
+	}
+	if ticketKeysFetch && len(ticketKeyFingerprints) == 0 {
+		return nil, fmt.Errorf("TICKET_KEY_FINGERPRINTS is required when TICKET_KEYS_FETCH=true")
+	}
+	ticketKeysTTLSec, _ := strconv.Atoi(getenv("TICKET_KEYS_TTL_SECONDS"))
+	if ticketKeysTTLSec <= 0 {
+		ticketKeysTTLSec = 300
+	}
+
+	clientCert := getenv("CLIENT_CERT_FILE")
+	clientKey := getenv("CLIENT_KEY_FILE")
+	caCert := getenv("CA_CERT_FILE")
+	// CA_CERT_FILE alone only replaces the roots TS is verified against
+	if (clientCert == "") != (clientKey == "") {
+		return nil, fmt.Errorf("CLIENT_CERT_FILE and CLIENT_KEY_FILE must be set together")
+	}
+
+	tlsMinVersion, err := parseTLSVersion(getenv("TLS_MIN_VERSION"))
+	if err != nil {
+		return nil, fmt.Errorf("TLS_MIN_VERSION: %w", err)
+	}
+
+	tlsCipherSuites, err := parseCipherSuites(splitList(getenv("TLS_CIPHER_SUITES")))
+	if err != nil {
+		return nil, fmt.Errorf("TLS_CIPHER_SUITES: %w", err)
+	}
+
+	extraHeaders, err := parseHeaders(getenv("EXTRA_HTTP_HEADERS"))
+	if err != nil {
+		return nil, fmt.Errorf("EXTRA_HTTP_HEADERS: %w", err)
+	}
+
+	workerID := getenv("WORKER_ID")
+	workerIDGenerated := workerID == ""
+	if workerIDGenerated {
 		hostname, _ := os.Hostname()
 		workerID = fmt.Sprintf("worker-%s-%d", hostname, os.Getpid())
 	}
 
-	pollSec, _ := strconv.Atoi(os.Getenv("POLL_INTERVAL_SECONDS"))
+	pollSec, _ := strconv.Atoi(getenv("POLL_INTERVAL_SECONDS"))
 	if pollSec <= 0 {
 		pollSec = 5
 	}
 
-	timeoutSec, _ := strconv.Atoi(os.Getenv("HTTP_TIMEOUT_SECONDS"))
+	maxPollSec, _ := strconv.Atoi(getenv("MAX_POLL_INTERVAL_SECONDS"))
+	if maxPollSec <= 0 {
+		maxPollSec = 30
+	}
+	maxPollSec = max(maxPollSec, pollSec)
+
+	claimFailureThreshold, _ := strconv.Atoi(getenv("CLAIM_FAILURE_THRESHOLD"))
+	if claimFailureThreshold <= 0 {
+		claimFailureThreshold = 10
+	}
+	startupGraceSec, _ := strconv.Atoi(getenv("STARTUP_GRACE_SECONDS"))
+	if startupGraceSec < 0 {
+		startupGraceSec = 0
+	}
+
+	breakerThreshold, err := strconv.Atoi(getenv("CIRCUIT_BREAKER_THRESHOLD"))
+	if err != nil || breakerThreshold < 0 {
+		breakerThreshold = 5
+	}
+	breakerCooldownSec, _ := strconv.Atoi(getenv("CIRCUIT_BREAKER_COOLDOWN_SECONDS"))
+	if breakerCooldownSec <= 0 {
+		breakerCooldownSec = 30
+	}
+	claimRetryAfterMaxSec, _ := strconv.Atoi(getenv("CLAIM_RETRY_AFTER_MAX_SECONDS"))
+	if claimRetryAfterMaxSec <= 0 {
+		claimRetryAfterMaxSec = 300
+	}
+
+	maxConcurrency, _ := strconv.Atoi(getenv("MAX_CONCURRENCY"))
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
+
+	autoConcurrency := getenv("AUTO_CONCURRENCY") == "true"
+	concurrencyMult, _ := strconv.ParseFloat(getenv("AUTO_CONCURRENCY_MULTIPLIER"), 64)
+	if concurrencyMult <= 0 {
+		concurrencyMult = 2
+	}
+	if autoConcurrency {
+		maxConcurrency = max(1, int(math.Ceil(float64(runtime.NumCPU())*concurrencyMult)))
+	}
+	latencySLOMs, _ := strconv.Atoi(getenv("AUTO_CONCURRENCY_LATENCY_SLO_MS"))
+	if latencySLOMs < 0 {
+		latencySLOMs = 0
+	}
+
+	batchSize, _ := strconv.Atoi(getenv("CLAIM_BATCH_SIZE"))
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	queueDepth, _ := strconv.Atoi(getenv("LOCAL_QUEUE_DEPTH"))
+	if queueDepth < 0 {
+		queueDepth = 0
+	}
+
+	timeoutSec, _ := strconv.Atoi(getenv("HTTP_TIMEOUT_SECONDS"))
 	if timeoutSec <= 0 {
 		timeoutSec = 30
 	}
 
+	maxIdleConns, _ := strconv.Atoi(getenv("MAX_IDLE_CONNS"))
+	if maxIdleConns <= 0 {
+		maxIdleConns = 10
+	}
+
+	maxConnsPerHost, _ := strconv.Atoi(getenv("MAX_CONNS_PER_HOST"))
+	if maxConnsPerHost < 0 {
+		maxConnsPerHost = 0
+	}
+
+	idleTimeoutSec, _ := strconv.Atoi(getenv("IDLE_CONN_TIMEOUT_SECONDS"))
+	if idleTimeoutSec <= 0 {
+		idleTimeoutSec = 90
+	}
+
+	dialTimeoutSec, _ := strconv.Atoi(getenv("DIAL_TIMEOUT_SECONDS"))
+	if dialTimeoutSec <= 0 {
+		dialTimeoutSec = 5
+	}
+
+	tlsHandshakeSec, _ := strconv.Atoi(getenv("TLS_HANDSHAKE_TIMEOUT_SECONDS"))
+	if tlsHandshakeSec <= 0 {
+		tlsHandshakeSec = 10
+	}
+
+	claimMode := getenv("CLAIM_MODE")
+	if claimMode == "" {
+		claimMode = "poll"
+	}
+	if claimMode != "poll" && claimMode != "longpoll" {
+		return nil, fmt.Errorf("CLAIM_MODE must be poll or longpoll, got %q", claimMode)
+	}
+
+	longPollSec, _ := strconv.Atoi(getenv("LONG_POLL_WAIT_SECONDS"))
+	if longPollSec <= 0 {
+		longPollSec = 20
+	}
+
+	// An explicit 0 releases running jobs' leases immediately on shutdown
+	drainSec, err := strconv.Atoi(getenv("SHUTDOWN_DRAIN_SECONDS"))
+	if err != nil || drainSec < 0 {
+		drainSec = 30
+	}
+
+	shutdownSec, _ := strconv.Atoi(getenv("SHUTDOWN_TIMEOUT_SECONDS"))
+	if shutdownSec <= 0 {
+		shutdownSec = drainSec + 10
+	}
+	if shutdownSec <= drainSec {
+		return nil, fmt.Errorf("SHUTDOWN_TIMEOUT_SECONDS (%d) must be greater than SHUTDOWN_DRAIN_SECONDS (%d)", shutdownSec, drainSec)
+	}
+
+	maxJobsBeforeExit, _ := strconv.Atoi(getenv("MAX_JOBS_BEFORE_EXIT"))
+	if maxJobsBeforeExit < 0 {
+		maxJobsBeforeExit = 0
+	}
+
+	maxLifetimeSec, _ := strconv.Atoi(getenv("MAX_LIFETIME_SECONDS"))
+	if maxLifetimeSec < 0 {
+		maxLifetimeSec = 0
+	}
+
+	hbThreshold, err := strconv.Atoi(getenv("HEARTBEAT_FAILURE_THRESHOLD"))
+	if err != nil || hbThreshold < 0 {
+		hbThreshold = 3
+	}
+
+	backoffBaseMs, _ := strconv.Atoi(getenv("RETRY_BACKOFF_BASE_MS"))
+	if backoffBaseMs < 0 {
+		backoffBaseMs = 0
+	}
+	backoffMaxMs, _ := strconv.Atoi(getenv("RETRY_BACKOFF_MAX_MS"))
+	if backoffMaxMs <= 0 {
+		backoffMaxMs = 300000
+	}
+
+	routing, err := loadRouting(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	sighupAction := cmp.Or(getenv("SIGHUP_ACTION"), "drain")
+	if sighupAction != "drain" && sighupAction != "reload" {
+		return nil, fmt.Errorf("SIGHUP_ACTION must be drain or reload, got %q", sighupAction)
+	}
+	if sighupAction == "reload" && os.Getenv("WORKER_CONFIG_FILE") == "" {
+		return nil, fmt.Errorf("SIGHUP_ACTION=reload requires WORKER_CONFIG_FILE")
+	}
+
+	visibilitySec, _ := strconv.Atoi(getenv("VISIBILITY_TIMEOUT_SECONDS"))
+	if visibilitySec < 0 {
+		visibilitySec = 0
+	}
+	jobTypeVisibility, err := parseJobTypeInts(getenv("JOBTYPE_VISIBILITY_TIMEOUT"))
+	if err != nil {
+		return nil, fmt.Errorf("JOBTYPE_VISIBILITY_TIMEOUT: %w", err)
+	}
+
+	maxRetries, err := strconv.Atoi(getenv("HTTP_MAX_RETRIES"))
+	if err != nil || maxRetries < 0 {
+		maxRetries = 3
+	}
+
+	retryBaseMs, _ := strconv.Atoi(getenv("HTTP_RETRY_BASE_MS"))
+	if retryBaseMs <= 0 {
+		retryBaseMs = 200
+	}
+
+	healthPort, _ := strconv.Atoi(getenv("HEALTH_PORT"))
+	if healthPort < 0 || healthPort > 65535 {
+		return nil, fmt.Errorf("HEALTH_PORT must be between 0 and 65535, got %d", healthPort)
+	}
+
+	metricsEnabled := getenv("METRICS_ENABLED") == "true"
+	if metricsEnabled && healthPort == 0 {
+		return nil, fmt.Errorf("METRICS_ENABLED requires HEALTH_PORT to be set")
+	}
+
+	debugEnabled := getenv("DEBUG_ENABLED") == "true"
+	if debugEnabled && healthPort == 0 {
+		return nil, fmt.Errorf("DEBUG_ENABLED requires HEALTH_PORT to be set")
+	}
+
+	nonceCacheSize, _ := strconv.Atoi(getenv("NONCE_CACHE_SIZE"))
+	if nonceCacheSize <= 0 {
+		nonceCacheSize = 10000
+	}
+
+	nonceMinBytes, err := strconv.Atoi(getenv("NONCE_MIN_BYTES"))
+	if err != nil || nonceMinBytes < 0 {
+		nonceMinBytes = 16 // 128-bit, a UUID
+	}
+
+	minPriority, _ := strconv.Atoi(getenv("CLAIM_MIN_PRIORITY"))
+	if minPriority < 0 {
+		return nil, fmt.Errorf("CLAIM_MIN_PRIORITY must not be negative, got %d", minPriority)
+	}
+
+	maxJobsPerSec, _ := strconv.ParseFloat(getenv("MAX_JOBS_PER_SECOND"), 64)
+	if maxJobsPerSec < 0 {
+		maxJobsPerSec = 0
+	}
+
+	claimBurst, _ := strconv.Atoi(getenv("CLAIM_BURST"))
+	if claimBurst <= 0 {
+		claimBurst = 1
+	}
+
+	maxPayload, _ := strconv.Atoi(getenv("MAX_PAYLOAD_BYTES"))
+	if maxPayload <= 0 {
+		maxPayload = 10 << 20 // 10 MiB
+	}
+
+	streamThreshold, _ := strconv.Atoi(getenv("RESULT_STREAM_THRESHOLD_BYTES"))
+	if streamThreshold <= 0 {
+		streamThreshold = 1 << 20 // 1 MiB
+	}
+
+	maxResult, _ := strconv.Atoi(getenv("MAX_RESULT_BYTES"))
+	if maxResult < 0 {
+		maxResult = 0
+	}
+	maxLogCapture, _ := strconv.Atoi(getenv("MAX_LOG_CAPTURE_BYTES"))
+	if maxLogCapture < 0 {
+		maxLogCapture = 0
+	}
+	maxInflight, _ := strconv.ParseInt(getenv("MAX_INFLIGHT_BYTES"), 10, 64)
+	if maxInflight < 0 {
+		maxInflight = 0
+	}
+
+	postMaxWaitSec, err := strconv.Atoi(getenv("RESULT_POST_MAX_WAIT_SECONDS"))
+	if err != nil || postMaxWaitSec < 0 {
+		postMaxWaitSec = 30
+	}
+	postBackoffMaxSec, _ := strconv.Atoi(getenv("RESULT_POST_BACKOFF_MAX_SECONDS"))
+	if postBackoffMaxSec <= 0 {
+		postBackoffMaxSec = 5
+	}
+
+	resultDedupSize, _ := strconv.Atoi(getenv("RESULT_DEDUP_CACHE_SIZE"))
+	if resultDedupSize <= 0 {
+		resultDedupSize = 10000
+	}
+
+	compressMin, _ := strconv.Atoi(getenv("COMPRESS_RESULTS_MIN_BYTES"))
+	if compressMin <= 0 {
+		compressMin = 4 << 10 // 4 KiB; smaller bodies gain little
+	}
+
+	skewMs, _ := strconv.Atoi(getenv("CLOCK_SKEW_MS"))
+	if skewMs < 0 {
+		skewMs = 0
+	}
+
+	resultSigVersion := 1
+	if v := getenv("RESULT_SIGNATURE_VERSION"); v != "" {
+		if resultSigVersion, err = strconv.Atoi(v); err != nil || resultSigVersion < 1 || resultSigVersion > 2 {
+			return nil, fmt.Errorf("RESULT_SIGNATURE_VERSION must be 1 or 2, got %q", v)
+		}
+	}
+
+	maxJobAgeSec, _ := strconv.Atoi(getenv("MAX_JOB_AGE_SECONDS"))
+	if maxJobAgeSec < 0 {
+		maxJobAgeSec = 0
+	}
+
+	crashRecovery := getenv("CRASH_RECOVERY") == "true"
+	stateDir := getenv("STATE_DIR")
+	if crashRecovery && stateDir == "" {
+		return nil, fmt.Errorf("CRASH_RECOVERY requires STATE_DIR to be set")
+	}
+
+	auditEnabled := getenv("AUDIT_ENABLED") == "true"
+	auditDir := getenv("AUDIT_DIR")
+	if auditEnabled && auditDir == "" {
+		return nil, fmt.Errorf("AUDIT_ENABLED requires AUDIT_DIR to be set")
+	}
+
+	otelEnabled := getenv("OTEL_ENABLED") == "true"
+	otelEndpoint := getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if otelEndpoint == "" {
+		otelEndpoint = "http://localhost:4318" // OTLP/HTTP default
+	}
+	otelService := getenv("OTEL_SERVICE_NAME")
+	if otelService == "" {
+		otelService = "coreos-worker"
+	}
+
+	logLevel, err := logging.ParseLevel(getenv("LOG_LEVEL"))
+	if err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+
+	logFormat, err := logging.ParseFormat(getenv("LOG_FORMAT"))
+	if err != nil {
+		return nil, fmt.Errorf("LOG_FORMAT: %w", err)
+	}
+	logSampleRate, _ := strconv.Atoi(getenv("LOG_SAMPLE_RATE"))
+	if logSampleRate <= 0 {
+		logSampleRate = 1
+	}
+
 	return &Config{
-		APIURL:          apiURL,
-		HMACSecret:      hmacSecret,
-		PublicKeyBase64: publicKey,
-		WorkerID:        workerID,
-		PollInterval:    time.Duration(pollSec) * time.Second,
-		HTTPTimeout:     time.Duration(timeoutSec) * time.Second,
+		APIURL:                    apiURLs[0],
+		APIStandbyURLs:            apiURLs[1:],
+		ResultURL:                 resultURL,
+		HeartbeatURL:              heartbeatURL,
+		HMACSecret:                hmacSecret,
+		AuthToken:                 getenv("WORKER_AUTH_TOKEN"),
+		ExtraHTTPHeaders:          extraHeaders,
+		WorkerIDHeader:            getenv("WORKER_ID_HEADER") == "true",
+		PublicKeyBase64:           publicKey,
+		TicketKeysFetch:           ticketKeysFetch,
+		TicketKeysTTL:             time.Duration(ticketKeysTTLSec) * time.Second,
+		TicketKeyFingerprints:     ticketKeyFingerprints,
+		PayloadEncryptionKey:      getenv("PAYLOAD_ENCRYPTION_KEY"),
+		ClientCertFile:            clientCert,
+		ClientKeyFile:             clientKey,
+		CACertFile:                caCert,
+		TLSMinVersion:             tlsMinVersion,
+		TLSCipherSuites:           tlsCipherSuites,
+		WorkerID:                  workerID,
+		WorkerIDGenerated:         workerIDGenerated,
+		AllowDuplicateWorkerID:    getenv("ALLOW_DUPLICATE_WORKER_ID") == "true",
+		ExitOnAuthFailure:         getenv("EXIT_ON_AUTH_FAILURE") == "true",
+		PollInterval:              time.Duration(pollSec) * time.Second,
+		PollJitter:                getenv("POLL_JITTER") == "true",
+		ClaimMode:                 claimMode,
+		LongPollWait:              time.Duration(longPollSec) * time.Second,
+		MaxPollInterval:           time.Duration(maxPollSec) * time.Second,
+		ClaimFailureThreshold:     claimFailureThreshold,
+		StartupGrace:              time.Duration(startupGraceSec) * time.Second,
+		CircuitBreakerThreshold:   breakerThreshold,
+		CircuitBreakerCooldown:    time.Duration(breakerCooldownSec) * time.Second,
+		ClaimRetryAfterMax:        time.Duration(claimRetryAfterMaxSec) * time.Second,
+		ClaimMinPriority:          minPriority,
+		ClaimAffinity:             getenv("CLAIM_AFFINITY") == "true",
+		ClaimBusyHint:             getenv("CLAIM_BUSY_HINT") == "true",
+		Routing:                   routing,
+		VisibilityTimeout:         time.Duration(visibilitySec) * time.Second,
+		JobTypeVisibilityTimeout:  jobTypeVisibility,
+		MaxConcurrency:            maxConcurrency,
+		AutoConcurrency:           autoConcurrency,
+		AutoConcurrencyMultiplier: concurrencyMult,
+		ConcurrencyLatencySLO:     time.Duration(latencySLOMs) * time.Millisecond,
+		MaxJobsPerSecond:          maxJobsPerSec,
+		ClaimBurst:                claimBurst,
+		ClaimBatchSize:            batchSize,
+		LocalQueueDepth:           queueDepth,
+		HTTPTimeout:               time.Duration(timeoutSec) * time.Second,
+		MaxIdleConns:              maxIdleConns,
+		MaxConnsPerHost:           maxConnsPerHost,
+		IdleConnTimeout:           time.Duration(idleTimeoutSec) * time.Second,
+		DialTimeout:               time.Duration(dialTimeoutSec) * time.Second,
+		TLSHandshakeTimeout:       time.Duration(tlsHandshakeSec) * time.Second,
+		ShutdownDrain:             time.Duration(drainSec) * time.Second,
+		ShutdownTimeout:           time.Duration(shutdownSec) * time.Second,
+		MaxJobsBeforeExit:         maxJobsBeforeExit,
+		MaxLifetime:               time.Duration(maxLifetimeSec) * time.Second,
+		HeartbeatFailureThreshold: hbThreshold,
+		RetryBackoffBase:          time.Duration(backoffBaseMs) * time.Millisecond,
+		RetryBackoffMax:           time.Duration(backoffMaxMs) * time.Millisecond,
+		HTTPMaxRetries:            maxRetries,
+		HTTPRetryBase:             time.Duration(retryBaseMs) * time.Millisecond,
+		HealthPort:                healthPort,
+		MetricsEnabled:            metricsEnabled,
+		DebugEnabled:              debugEnabled,
+		MaxPayloadBytes:           maxPayload,
+		ResultStreamThreshold:     streamThreshold,
+		MaxResultBytes:            maxResult,
+		MaxLogCaptureBytes:        maxLogCapture,
+		MaxInflightBytes:          maxInflight,
+		ResultPostMaxWait:         time.Duration(postMaxWaitSec) * time.Second,
+		ResultPostBackoffMax:      time.Duration(postBackoffMaxSec) * time.Second,
+		ResultSpoolDir:            getenv("RESULT_SPOOL_DIR"),
+		CompressResults:           getenv("COMPRESS_RESULTS") == "true",
+		CompressResultsMinBytes:   compressMin,
+		ResultDedupCacheSize:      resultDedupSize,
+		NonceCacheSize:            nonceCacheSize,
+		NonceMinBytes:             nonceMinBytes,
+		LogLevel:                  logLevel,
+		LogFormat:                 logFormat,
+		LogSampleRate:             logSampleRate,
+		TraceW3C:                  getenv("TRACE_W3C") == "true",
+		OTelEnabled:               otelEnabled,
+		OTelEndpoint:              otelEndpoint,
+		OTelServiceName:           otelService,
+		DryRun:                    getenv("DRY_RUN") == "true",
+		ClockSkew:                 time.Duration(skewMs) * time.Millisecond,
+		ResultSignatureVersion:    resultSigVersion,
+		MaxJobAge:                 time.Duration(maxJobAgeSec) * time.Second,
+		ExpectAck:                 getenv("EXPECT_RESULT_ACK") == "true",
+		CrashRecovery:             crashRecovery,
+		StateDir:                  stateDir,
+		AuditEnabled:              auditEnabled,
+		AuditDir:                  auditDir,
+		RecordEnvelopesDir:        getenv("RECORD_ENVELOPES_DIR"),
+		EnableTestHandlers:        getenv("ENABLE_TEST_HANDLERS") == "true",
+		AllowPrivateTargets:       getenv("ALLOW_PRIVATE_TARGETS") == "true",
+
+		DeadLetterWebhookURL: getenv("DEAD_LETTER_WEBHOOK_URL"),
+		ConfigFile:           os.Getenv("WORKER_CONFIG_FILE"),
+		SighupAction:         sighupAction,
 	}, nil
 }
+
+// parseKeyFingerprints normalises "SHA256:<hex>" (as printed by
+// KeyFingerprint) or bare hex entries to lowercase hex. Pins are full
+// SHA-256 digests: they are the only check on keys fetched from TS.
+func parseKeyFingerprints(items []string) ([]string, error) {
+	var out []string
+	for _, item := range items {
+		fp := strings.ToLower(strings.TrimPrefix(item, "SHA256:"))
+		if _, err := hex.DecodeString(fp); err != nil || len(fp) != 2*sha256.Size {
+			return nil, fmt.Errorf("invalid fingerprint %q (want SHA256:<64 hex digits>)", item)
+		}
+		out = append(out, fp)
+	}
+	return out, nil
+}
+
+// normalizeBaseURL validates a TS base URL (http or https, with a host, no
+// path, query or fragment) and strips trailing slashes, so joining an API
+// path never yields "//api/...". "" is returned as is.
+func normalizeBaseURL(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme == "" {
+		return "", fmt.Errorf("invalid URL %q: missing scheme (want http:// or https://)", raw)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid URL %q: scheme must be http or https", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid URL %q: missing host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || u.ForceQuery {
+		return "", fmt.Errorf("invalid URL %q: query and fragment are not allowed", raw)
+	}
+	if strings.TrimRight(u.Path, "/") != "" {
+		return "", fmt.Errorf("invalid URL %q: path is not allowed (API paths are added by the worker)", raw)
+	}
+	u.Path, u.RawPath = "", ""
+	return u.String(), nil
+}
+
+// splitList parses a comma-separated env value, dropping blank entries.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// parseJobTypeInts parses "jobType:n,jobType:n" into per-jobType values.
+func parseJobTypeInts(v string) (map[string]int, error) {
+	caps := make(map[string]int)
+	for _, item := range splitList(v) {
+		jobType, n, ok := strings.Cut(item, ":")
+		jobType = strings.TrimSpace(jobType)
+		limit, err := strconv.Atoi(strings.TrimSpace(n))
+		if !ok || jobType == "" || err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid entry %q (want jobType:n with n > 0)", item)
+		}
+		caps[jobType] = limit
+	}
+	return caps, nil
+}
+
+// parseHeaders parses "Name=value,Name=value" into header values, keyed by
+// canonical name.
+func parseHeaders(v string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, item := range splitList(v) {
+		name, value, ok := strings.Cut(item, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || !validHeaderName(name) || !validHeaderValue(value) {
+			return nil, fmt.Errorf("invalid entry %q (want Name=value)", item)
+		}
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+	return headers, nil
+}
+
+// validHeaderName reports whether name is a non-empty HTTP token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r >= 0x7f || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// validHeaderValue reports whether value has no control characters.
+func validHeaderValue(value string) bool {
+	for _, r := range value {
+		if (r < ' ' && r != '\t') || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// VisibilityTimeoutFor returns the lease length to request for jobType
+// (0 = TS default).
+func (c *Config) VisibilityTimeoutFor(jobType string) time.Duration {
+	if sec, ok := c.JobTypeVisibilityTimeout[jobType]; ok {
+		return time.Duration(sec) * time.Second
+	}
+	return c.VisibilityTimeout
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr string // substring of the error; "" = no error
+	}{
+		{name: "empty", raw: "", want: ""},
+		{name: "plain", raw: "http://coreos-ts:3001", want: "http://coreos-ts:3001"},
+		{name: "trailing slash", raw: "https://ts.example.com/", want: "https://ts.example.com"},
+		{name: "trailing slashes", raw: "https://ts.example.com///", want: "https://ts.example.com"},
+		{name: "missing scheme", raw: "ts.example.com", wantErr: "missing scheme"},
+		{name: "missing scheme with port", raw: "localhost:3001", wantErr: "scheme must be http or https"},
+		{name: "unsupported scheme", raw: "ftp://ts.example.com", wantErr: "scheme must be http or https"},
+		{name: "missing host", raw: "http:///api", wantErr: "missing host"},
+		{name: "path", raw: "https://ts.example.com/coreos", wantErr: "path is not allowed"},
+		{name: "path with trailing slash", raw: "https://ts.example.com/coreos/", wantErr: "path is not allowed"},
+		{name: "query", raw: "https://ts.example.com?region=eu", wantErr: "query and fragment"},
+		{name: "empty query", raw: "https://ts.example.com?", wantErr: "query and fragment"},
+		{name: "fragment", raw: "https://ts.example.com#top", wantErr: "query and fragment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeBaseURL(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("normalizeBaseURL(%q) error = %v, want it to contain %q", tt.raw, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("normalizeBaseURL(%q) = %q, %v, want %q", tt.raw, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadNormalizesBaseURLs(t *testing.T) {
+	urlVars := []struct {
+		env string
+		got func(*Config) string
+	}{
+		{"COREOS_API_URL", func(c *Config) string { return c.APIURL }},
+		{"COREOS_RESULT_URL", func(c *Config) string { return c.ResultURL }},
+		{"COREOS_HEARTBEAT_URL", func(c *Config) string { return c.HeartbeatURL }},
+	}
+	for _, v := range urlVars {
+		t.Run(v.env, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv(v.env, "https://ts.example.com/")
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := v.got(cfg); got != "https://ts.example.com" {
+				t.Errorf("%s = %q, want trailing slash stripped", v.env, got)
+			}
+
+			for _, bad := range []string{"ts.example.com", "https://ts.example.com/api", "https://ts.example.com?x=1"} {
+				t.Setenv(v.env, bad)
+				if _, err := Load(); err == nil || !strings.HasPrefix(err.Error(), v.env+":") {
+					t.Errorf("Load() with %s=%q error = %v, want a %s error", v.env, bad, err, v.env)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadNormalizesStandbyURLs(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("COREOS_API_URL", "https://primary.example.com/, https://standby.example.com/")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.APIURL != "https://primary.example.com" {
+		t.Errorf("APIURL = %q", cfg.APIURL)
+	}
+	if len(cfg.APIStandbyURLs) != 1 || cfg.APIStandbyURLs[0] != "https://standby.example.com" {
+		t.Errorf("APIStandbyURLs = %q", cfg.APIStandbyURLs)
+	}
+}
+
+// setRequiredEnv sets the variables Load requires.
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("WORKER_CONFIG_FILE", "")
+	t.Setenv("COREOS_API_URL", "http://coreos-ts:3001")
+	t.Setenv("JOB_WORKER_HMAC_SECRET", "test-secret")
+	t.Setenv("JOB_TICKET_PUBLIC_KEY", "dGVzdC1wdWJsaWMta2V5")
+}
+
+func TestLoadTLSFiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		cert, key, ca string
+		wantErr       bool
+	}{
+		{name: "none"},
+		{name: "CA only", ca: "/etc/coreos/ca.pem"},
+		{name: "client pair", cert: "/etc/coreos/client.pem", key: "/etc/coreos/client-key.pem"},
+		{name: "client pair and CA", cert: "/etc/coreos/client.pem", key: "/etc/coreos/client-key.pem", ca: "/etc/coreos/ca.pem"},
+		{name: "cert without key", cert: "/etc/coreos/client.pem", wantErr: true},
+		{name: "key without cert", key: "/etc/coreos/client-key.pem", ca: "/etc/coreos/ca.pem", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("CLIENT_CERT_FILE", tt.cert)
+			t.Setenv("CLIENT_KEY_FILE", tt.key)
+			t.Setenv("CA_CERT_FILE", tt.ca)
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Load() error = nil, want CLIENT_CERT_FILE/CLIENT_KEY_FILE error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.CACertFile != tt.ca {
+				t.Errorf("CACertFile = %q, want %q", cfg.CACertFile, tt.ca)
+			}
+		})
+	}
+}
+
+func TestLoadShutdownDrain(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want time.Duration
+	}{
+		{"", 30 * time.Second},
+		{"0", 0},
+		{"5", 5 * time.Second},
+		{"-1", 30 * time.Second},
+		{"soon", 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("SHUTDOWN_DRAIN_SECONDS", tt.raw)
+			t.Setenv("SHUTDOWN_TIMEOUT_SECONDS", "")
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.ShutdownDrain != tt.want {
+				t.Errorf("ShutdownDrain = %v, want %v", cfg.ShutdownDrain, tt.want)
+			}
+			if cfg.ShutdownTimeout <= cfg.ShutdownDrain {
+				t.Errorf("ShutdownTimeout = %v, want more than the drain", cfg.ShutdownTimeout)
+			}
+		})
+	}
+}
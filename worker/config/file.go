@@ -0,0 +1,206 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Config File (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// WORKER_CONFIG_FILE may point at a JSON or YAML file whose keys are the
+// same names as the env vars (COREOS_API_URL, JOB_WORKER_HMAC_SECRET,
+// POLL_INTERVAL_SECONDS, ...). An env var that is set always wins over the
+// file, and the merged values go through the same parsing and validation.
+//
+// JSON: a flat object; numbers and booleans are taken as written, arrays
+// become comma-separated lists (COREOS_API_URL, JOB_TYPE_ALLOW) and
+// objects become "key:value" lists (JOBTYPE_CONCURRENCY).
+// YAML (.yaml/.yml): the flat subset of the same — "KEY: value" lines,
+// optional quotes, # comments, and lists either inline ([a, b]) or as
+// "- item" lines under the key.
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// configSource returns the lookup Load reads settings through: the env
+// var if set, else the value from WORKER_CONFIG_FILE (if any).
+func configSource() (func(key string) string, error) {
+	path := os.Getenv("WORKER_CONFIG_FILE")
+	if path == "" {
+		return os.Getenv, nil
+	}
+	file, err := readConfigFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("WORKER_CONFIG_FILE: %w", err)
+	}
+	return func(key string) string {
+		if v, ok := os.LookupEnv(key); ok {
+			return v
+		}
+		return file[key]
+	}, nil
+}
+
+// readConfigFile parses a JSON or YAML config file into env-style values.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAMLConfig(data)
+	default:
+		return parseJSONConfig(data)
+	}
+}
+
+func parseJSONConfig(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	values := make(map[string]string, len(raw))
+	for key, v := range raw {
+		s, err := jsonConfigValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		values[key] = s
+	}
+	return values, nil
+}
+
+// jsonConfigValue renders a JSON value the way it would be written in the env.
+func jsonConfigValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := jsonConfigValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			s, err := jsonConfigValue(v[k])
+			if err != nil {
+				return "", err
+			}
+			items[i] = k + ":" + s
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", v)
+	}
+}
+
+func parseYAMLConfig(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	var listKey string // key whose "- item" lines are being read
+	var list []string
+	flush := func() {
+		if listKey != "" {
+			values[listKey] = strings.Join(list, ",")
+			listKey, list = "", nil
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := stripYAMLComment(scanner.Text())
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && listKey != "" {
+			list = append(list, unquoteYAML(strings.TrimSpace(item)))
+			continue
+		}
+		flush()
+		if line[0] == ' ' || line[0] == '\t' {
+			return nil, fmt.Errorf("line %d: nested YAML is not supported", n)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"KEY: value\"", n)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch {
+		case value == "":
+			listKey = key // a block list may follow; otherwise empty
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			var items []string
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, unquoteYAML(item))
+				}
+			}
+			values[key] = strings.Join(items, ",")
+		default:
+			values[key] = unquoteYAML(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return values, nil
+}
+
+// stripYAMLComment drops a "#" comment that isn't inside quotes.
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquoteYAML(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '"' {
+			if u, err := strconv.Unquote(s); err == nil {
+				return u
+			}
+		}
+		return s[1 : len(s)-1]
+	}
+	return s
+}
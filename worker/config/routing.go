@@ -0,0 +1,64 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — jobType Routing (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// JOB_TYPE_ALLOW, JOB_TYPE_DENY and JOBTYPE_CONCURRENCY decide which jobs
+// this worker claims and how many of each run at once. With
+// SIGHUP_ACTION=reload they are re-read from WORKER_CONFIG_FILE on SIGHUP,
+// so a misbehaving jobType can be shed without a restart. Env vars still
+// take precedence over the file, so a routing setting that is set in the
+// env cannot be changed by a reload.
+
+package config
+
+import "fmt"
+
+// Routing is the jobType routing that can be reloaded at runtime.
+type Routing struct {
+	// jobTypes this worker will run (empty = all) / never run
+	JobTypeAllow []string
+	JobTypeDeny  []string
+
+	// Per-jobType cap on concurrently executing jobs (unlisted = MaxConcurrency only)
+	JobTypeConcurrency map[string]int
+}
+
+func loadRouting(getenv func(string) string) (Routing, error) {
+	jobTypeConcurrency, err := parseJobTypeInts(getenv("JOBTYPE_CONCURRENCY"))
+	if err != nil {
+		return Routing{}, fmt.Errorf("JOBTYPE_CONCURRENCY: %w", err)
+	}
+	return Routing{
+		JobTypeAllow:       splitList(getenv("JOB_TYPE_ALLOW")),
+		JobTypeDeny:        splitList(getenv("JOB_TYPE_DENY")),
+		JobTypeConcurrency: jobTypeConcurrency,
+	}, nil
+}
+
+// ReloadRouting re-reads the routing settings from the env and
+// WORKER_CONFIG_FILE. On error the caller should keep its current routing.
+func ReloadRouting() (Routing, error) {
+	getenv, err := configSource()
+	if err != nil {
+		return Routing{}, err
+	}
+	return loadRouting(getenv)
+}
+
+// JobTypeAllowed reports whether jobType passes the allow/deny lists.
+func (r *Routing) JobTypeAllowed(jobType string) bool {
+	for _, t := range r.JobTypeDeny {
+		if t == jobType {
+			return false
+		}
+	}
+	if len(r.JobTypeAllow) == 0 {
+		return true
+	}
+	for _, t := range r.JobTypeAllow {
+		if t == jobType {
+			return true
+		}
+	}
+	return false
+}
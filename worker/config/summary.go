@@ -0,0 +1,188 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Effective Configuration Summary (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Human-readable dump of the resolved config for --check-config.
+// Secrets are never printed: the HMAC secret and payload key are redacted
+// and the public key is shown only as a fingerprint.
+
+package config
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteSummary prints the effective configuration, one env var per line.
+func (c *Config) WriteSummary(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(key, value string) {
+		fmt.Fprintf(tw, "%s\t%s\n", key, value)
+	}
+
+	row("WORKER_CONFIG_FILE", orNone(c.ConfigFile))
+	row("SIGHUP_ACTION", c.SighupAction)
+	row("COREOS_API_URL", strings.Join(append([]string{c.APIURL}, c.APIStandbyURLs...), ","))
+	row("COREOS_RESULT_URL", cmp.Or(c.ResultURL, "(COREOS_API_URL)"))
+	row("COREOS_HEARTBEAT_URL", cmp.Or(c.HeartbeatURL, "(COREOS_API_URL)"))
+	row("JOB_WORKER_HMAC_SECRET", redact(c.HMACSecret))
+	row("JOB_TICKET_PUBLIC_KEY", KeyFingerprint(c.PublicKeyBase64))
+	row("TICKET_KEYS_FETCH", strconv.FormatBool(c.TicketKeysFetch))
+	row("TICKET_KEYS_TTL_SECONDS", c.TicketKeysTTL.String())
+	row("TICKET_KEY_FINGERPRINTS", orNone(strings.Join(c.TicketKeyFingerprints, ",")))
+	row("PAYLOAD_ENCRYPTION_KEY", redact(c.PayloadEncryptionKey))
+	row("WORKER_AUTH_TOKEN", redact(c.AuthToken))
+	row("WORKER_ID_HEADER", strconv.FormatBool(c.WorkerIDHeader))
+	row("EXTRA_HTTP_HEADERS", orNone(headerNames(c.ExtraHTTPHeaders)))
+	row("CLIENT_CERT_FILE", orNone(c.ClientCertFile))
+	row("CLIENT_KEY_FILE", orNone(c.ClientKeyFile))
+	row("CA_CERT_FILE", orNone(c.CACertFile))
+	row("TLS_MIN_VERSION", tls.VersionName(c.TLSMinVersion))
+	row("TLS_CIPHER_SUITES", orNone(cipherSuiteNames(c.TLSCipherSuites)))
+	row("WORKER_ID", c.WorkerID)
+	row("ALLOW_DUPLICATE_WORKER_ID", strconv.FormatBool(c.AllowDuplicateWorkerID))
+	row("EXIT_ON_AUTH_FAILURE", strconv.FormatBool(c.ExitOnAuthFailure))
+	row("POLL_INTERVAL_SECONDS", c.PollInterval.String())
+	row("CLAIM_MODE", c.ClaimMode)
+	row("LONG_POLL_WAIT_SECONDS", c.LongPollWait.String())
+	row("POLL_JITTER", strconv.FormatBool(c.PollJitter))
+	row("MAX_POLL_INTERVAL_SECONDS", c.MaxPollInterval.String())
+	row("CLAIM_FAILURE_THRESHOLD", strconv.Itoa(c.ClaimFailureThreshold))
+	row("STARTUP_GRACE_SECONDS", c.StartupGrace.String())
+	row("CIRCUIT_BREAKER_THRESHOLD", strconv.Itoa(c.CircuitBreakerThreshold))
+	row("CIRCUIT_BREAKER_COOLDOWN_SECONDS", c.CircuitBreakerCooldown.String())
+	row("CLAIM_RETRY_AFTER_MAX_SECONDS", c.ClaimRetryAfterMax.String())
+	row("CLAIM_MIN_PRIORITY", strconv.Itoa(c.ClaimMinPriority))
+	row("CLAIM_AFFINITY", strconv.FormatBool(c.ClaimAffinity))
+	row("CLAIM_BUSY_HINT", strconv.FormatBool(c.ClaimBusyHint))
+	row("JOB_TYPE_ALLOW", orNone(strings.Join(c.JobTypeAllow, ",")))
+	row("JOB_TYPE_DENY", orNone(strings.Join(c.JobTypeDeny, ",")))
+	row("JOBTYPE_CONCURRENCY", orNone(jobTypeCaps(c.JobTypeConcurrency)))
+	row("VISIBILITY_TIMEOUT_SECONDS", c.VisibilityTimeout.String())
+	row("JOBTYPE_VISIBILITY_TIMEOUT", orNone(jobTypeCaps(c.JobTypeVisibilityTimeout)))
+	row("MAX_CONCURRENCY", strconv.Itoa(c.MaxConcurrency))
+	row("AUTO_CONCURRENCY", strconv.FormatBool(c.AutoConcurrency))
+	row("AUTO_CONCURRENCY_MULTIPLIER", strconv.FormatFloat(c.AutoConcurrencyMultiplier, 'g', -1, 64))
+	row("AUTO_CONCURRENCY_LATENCY_SLO_MS", c.ConcurrencyLatencySLO.String())
+	row("MAX_JOBS_PER_SECOND", strconv.FormatFloat(c.MaxJobsPerSecond, 'g', -1, 64))
+	row("CLAIM_BURST", strconv.Itoa(c.ClaimBurst))
+	row("CLAIM_BATCH_SIZE", strconv.Itoa(c.ClaimBatchSize))
+	row("LOCAL_QUEUE_DEPTH", strconv.Itoa(c.LocalQueueDepth))
+	row("HTTP_TIMEOUT_SECONDS", c.HTTPTimeout.String())
+	row("MAX_IDLE_CONNS", strconv.Itoa(c.MaxIdleConns))
+	row("MAX_CONNS_PER_HOST", strconv.Itoa(c.MaxConnsPerHost))
+	row("IDLE_CONN_TIMEOUT_SECONDS", c.IdleConnTimeout.String())
+	row("DIAL_TIMEOUT_SECONDS", c.DialTimeout.String())
+	row("TLS_HANDSHAKE_TIMEOUT_SECONDS", c.TLSHandshakeTimeout.String())
+	row("SHUTDOWN_DRAIN_SECONDS", c.ShutdownDrain.String())
+	row("SHUTDOWN_TIMEOUT_SECONDS", c.ShutdownTimeout.String())
+	row("MAX_JOBS_BEFORE_EXIT", strconv.Itoa(c.MaxJobsBeforeExit))
+	row("MAX_LIFETIME_SECONDS", c.MaxLifetime.String())
+	row("HEARTBEAT_FAILURE_THRESHOLD", strconv.Itoa(c.HeartbeatFailureThreshold))
+	row("RETRY_BACKOFF_BASE_MS", c.RetryBackoffBase.String())
+	row("RETRY_BACKOFF_MAX_MS", c.RetryBackoffMax.String())
+	row("HTTP_MAX_RETRIES", strconv.Itoa(c.HTTPMaxRetries))
+	row("HTTP_RETRY_BASE_MS", c.HTTPRetryBase.String())
+	row("HEALTH_PORT", strconv.Itoa(c.HealthPort))
+	row("METRICS_ENABLED", strconv.FormatBool(c.MetricsEnabled))
+	row("DEBUG_ENABLED", strconv.FormatBool(c.DebugEnabled))
+	row("MAX_PAYLOAD_BYTES", strconv.Itoa(c.MaxPayloadBytes))
+	row("RESULT_STREAM_THRESHOLD_BYTES", strconv.Itoa(c.ResultStreamThreshold))
+	row("RESULT_POST_MAX_WAIT_SECONDS", c.ResultPostMaxWait.String())
+	row("RESULT_POST_BACKOFF_MAX_SECONDS", c.ResultPostBackoffMax.String())
+	row("RESULT_SPOOL_DIR", orNone(c.ResultSpoolDir))
+	row("COMPRESS_RESULTS", strconv.FormatBool(c.CompressResults))
+	row("COMPRESS_RESULTS_MIN_BYTES", strconv.Itoa(c.CompressResultsMinBytes))
+	row("RESULT_DEDUP_CACHE_SIZE", strconv.Itoa(c.ResultDedupCacheSize))
+	row("MAX_RESULT_BYTES", strconv.Itoa(c.MaxResultBytes))
+	row("MAX_LOG_CAPTURE_BYTES", strconv.Itoa(c.MaxLogCaptureBytes))
+	row("MAX_INFLIGHT_BYTES", strconv.FormatInt(c.MaxInflightBytes, 10))
+	row("NONCE_CACHE_SIZE", strconv.Itoa(c.NonceCacheSize))
+	row("NONCE_MIN_BYTES", strconv.Itoa(c.NonceMinBytes))
+	row("LOG_LEVEL", c.LogLevel.String())
+	row("LOG_FORMAT", c.LogFormat)
+	row("LOG_SAMPLE_RATE", strconv.Itoa(c.LogSampleRate))
+	row("TRACE_W3C", strconv.FormatBool(c.TraceW3C))
+	row("OTEL_ENABLED", strconv.FormatBool(c.OTelEnabled))
+	row("OTEL_EXPORTER_OTLP_ENDPOINT", c.OTelEndpoint)
+	row("OTEL_SERVICE_NAME", c.OTelServiceName)
+	row("DRY_RUN", strconv.FormatBool(c.DryRun))
+	row("CLOCK_SKEW_MS", c.ClockSkew.String())
+	row("RESULT_SIGNATURE_VERSION", strconv.Itoa(c.ResultSignatureVersion))
+	row("MAX_JOB_AGE_SECONDS", c.MaxJobAge.String())
+	row("EXPECT_RESULT_ACK", strconv.FormatBool(c.ExpectAck))
+	row("CRASH_RECOVERY", strconv.FormatBool(c.CrashRecovery))
+	row("STATE_DIR", orNone(c.StateDir))
+	row("AUDIT_ENABLED", strconv.FormatBool(c.AuditEnabled))
+	row("AUDIT_DIR", orNone(c.AuditDir))
+	row("RECORD_ENVELOPES_DIR", orNone(c.RecordEnvelopesDir))
+	row("ENABLE_TEST_HANDLERS", strconv.FormatBool(c.EnableTestHandlers))
+	row("ALLOW_PRIVATE_TARGETS", strconv.FormatBool(c.AllowPrivateTargets))
+	row("DEAD_LETTER_WEBHOOK_URL", orNone(c.DeadLetterWebhookURL))
+
+	tw.Flush()
+}
+
+// KeyFingerprint returns the SHA-256 fingerprint of a base64 key, in the
+// form TICKET_KEY_FINGERPRINTS accepts.
+func KeyFingerprint(keyBase64 string) string {
+	raw, err := base64.StdEncoding.DecodeString(keyBase64)
+	if err != nil {
+		return "(invalid base64)"
+	}
+	sum := sha256.Sum256(raw)
+	return "SHA256:" + hex.EncodeToString(sum[:])
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return "(unset)"
+	}
+	return fmt.Sprintf("(redacted, %d bytes)", len(secret))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// jobTypeCaps formats per-jobType values as a sorted "jobType:n" list.
+func jobTypeCaps(caps map[string]int) string {
+	items := make([]string, 0, len(caps))
+	for jobType, n := range caps {
+		items = append(items, jobType+":"+strconv.Itoa(n))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// headerNames formats header names as a sorted list; values may carry
+// credentials, so they are left out.
+func headerNames(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// cipherSuiteNames formats cipher suite IDs as a comma-separated list.
+func cipherSuiteNames(ids []uint16) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = tls.CipherSuiteName(id)
+	}
+	return strings.Join(names, ",")
+}
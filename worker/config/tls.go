@@ -0,0 +1,55 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Worker TLS Settings (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Parsing for TLS_MIN_VERSION and TLS_CIPHER_SUITES.
+
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// parseTLSVersion parses TLS_MIN_VERSION ("1.2" or "1.3", default 1.2).
+func parseTLSVersion(s string) (uint16, error) {
+	switch s {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid TLS version %q (accepted: 1.2, 1.3)", s)
+	}
+}
+
+// parseCipherSuites parses TLS_CIPHER_SUITES, a comma-separated list of Go
+// cipher suite names (e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256). Only
+// secure TLS 1.2 suites are accepted (TLS 1.3 suites are not configurable
+// in Go). Empty = Go defaults.
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	var accepted []string
+	for _, cs := range tls.CipherSuites() {
+		if slices.Contains(cs.SupportedVersions, tls.VersionTLS12) {
+			known[cs.Name] = cs.ID
+			accepted = append(accepted, cs.Name)
+		}
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q (accepted: %s)", name, strings.Join(accepted, ", "))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,47 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Result Acknowledgement Contract (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// TS acknowledges a received JobResult with an HMAC-SHA256 (shared secret)
+// over { jobId, nonce } so a proxy cannot fake a successful callback.
+
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ResultAck is the signed acknowledgement returned by POST /api/jobs/result.
+type ResultAck struct {
+	JobID     string `json:"jobId"`
+	Nonce     string `json:"nonce"`
+	Signature string `json:"signature"`
+}
+
+// ackSignableData is the structure used for ack HMAC computation.
+// Keys are sorted alphabetically to match TS canonical JSON.
+type ackSignableData struct {
+	JobID string `json:"jobId"`
+	Nonce string `json:"nonce"`
+}
+
+// Verify checks that the ack is for jobID and was signed with secret.
+func (a *ResultAck) Verify(jobID, secret string) error {
+	if a.JobID != jobID {
+		return fmt.Errorf("ack jobId mismatch: expected %s, got %s", jobID, a.JobID)
+	}
+	if a.Nonce == "" {
+		return fmt.Errorf("ack nonce is empty")
+	}
+
+	b, err := json.Marshal(ackSignableData{JobID: a.JobID, Nonce: a.Nonce})
+	if err != nil {
+		return fmt.Errorf("failed to marshal ack signable data: %w", err)
+	}
+
+	if err := VerifyHMAC(secret, string(b), a.Signature); err != nil {
+		return fmt.Errorf("invalid ack signature: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — HMAC Helpers (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// HMAC-SHA256 (shared secret) signing and verification.
+// All verification of HMACs received from TS must go through VerifyHMAC,
+// which compares in constant time so a mismatch leaks no timing oracle.
+
+package contracts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrHMACMismatch is returned when a signature does not match the message.
+var ErrHMACMismatch = errors.New("hmac signature mismatch")
+
+// ComputeHMAC returns the hex HMAC-SHA256 of message.
+func ComputeHMAC(secret, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMAC checks a hex HMAC-SHA256 of message using a constant-time
+// comparison.
+func VerifyHMAC(secret, message, providedHex string) error {
+	provided, err := hex.DecodeString(providedHex)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	if !hmac.Equal(mac.Sum(nil), provided) {
+		return ErrHMACMismatch
+	}
+	return nil
+}
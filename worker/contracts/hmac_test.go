@@ -0,0 +1,44 @@
+package contracts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerifyHMAC(t *testing.T) {
+	const secret, message = "test-secret", `{"jobId":"job-1"}`
+	valid := ComputeHMAC(secret, message)
+
+	tests := []struct {
+		name      string
+		secret    string
+		message   string
+		signature string
+		want      string // "ok", "mismatch" or "malformed"
+	}{
+		{"matching", secret, message, valid, "ok"},
+		{"matching uppercase hex", secret, message, strings.ToUpper(valid), "ok"},
+		{"wrong secret", "other-secret", message, valid, "mismatch"},
+		{"altered message", secret, `{"jobId":"job-2"}`, valid, "mismatch"},
+		{"truncated signature", secret, message, valid[:32], "mismatch"},
+		{"empty signature", secret, message, "", "mismatch"},
+		{"non-hex signature", secret, message, "zz" + valid[2:], "malformed"},
+		{"odd-length signature", secret, message, valid[1:], "malformed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyHMAC(tt.secret, tt.message, tt.signature)
+			got := "ok"
+			switch {
+			case errors.Is(err, ErrHMACMismatch):
+				got = "mismatch"
+			case err != nil:
+				got = "malformed"
+			}
+			if got != tt.want {
+				t.Fatalf("VerifyHMAC() = %v (%s), want %s", err, got, tt.want)
+			}
+		})
+	}
+}
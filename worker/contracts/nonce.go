@@ -0,0 +1,105 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — JobTicket Nonce Cache (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// In-memory replay protection for JobTicket nonces.
+// Entries live until the ticket's Deadline (ExpiresAt plus ClockSkewMs), after
+// which a replay would be rejected by ValidateExpiry anyway. Bounded and safe
+// for concurrent use.
+
+package contracts
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNonceReplay is returned when a ticket nonce has already been seen.
+var ErrNonceReplay = errors.New("ticket nonce already used")
+
+// NonceCache records seen nonces until their ticket expires.
+type NonceCache struct {
+	mu      sync.Mutex
+	maxSize int
+	seen    map[string]int64 // nonce → expiresAt (unix ms)
+	byExp   nonceHeap
+}
+
+// NewNonceCache creates a cache holding at most maxSize live nonces.
+func NewNonceCache(maxSize int) *NonceCache {
+	if maxSize <= 0 {
+		maxSize = 10000
+	}
+	return &NonceCache{
+		maxSize: maxSize,
+		seen:    make(map[string]int64),
+	}
+}
+
+// CheckAndStore records the nonce, or returns ErrNonceReplay if it is
+// already present and its ticket had not expired at now.
+func (c *NonceCache) CheckAndStore(nonce string, expiresAt int64, now time.Time) error {
+	nowMs := now.UnixMilli()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpired(nowMs)
+
+	if _, ok := c.seen[nonce]; ok {
+		return fmt.Errorf("%w: %s", ErrNonceReplay, nonce)
+	}
+
+	// Full: drop the entry closest to expiry to stay bounded.
+	for len(c.seen) >= c.maxSize && c.byExp.Len() > 0 {
+		oldest := heap.Pop(&c.byExp).(nonceEntry)
+		delete(c.seen, oldest.nonce)
+	}
+
+	c.seen[nonce] = expiresAt
+	heap.Push(&c.byExp, nonceEntry{nonce: nonce, expiresAt: expiresAt})
+	return nil
+}
+
+// Len returns the number of nonces currently tracked.
+func (c *NonceCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.seen)
+}
+
+// evictExpired removes entries whose ticket has expired. Caller holds mu.
+func (c *NonceCache) evictExpired(now int64) {
+	for c.byExp.Len() > 0 && c.byExp[0].expiresAt <= now {
+		oldest := heap.Pop(&c.byExp).(nonceEntry)
+		delete(c.seen, oldest.nonce)
+	}
+}
+
+// nonceEntry is a heap element ordered by expiry.
+type nonceEntry struct {
+	nonce     string
+	expiresAt int64
+}
+
+// nonceHeap is a min-heap of nonce entries by expiresAt.
+type nonceHeap []nonceEntry
+
+func (h nonceHeap) Len() int           { return len(h) }
+func (h nonceHeap) Less(i, j int) bool { return h[i].expiresAt < h[j].expiresAt }
+func (h nonceHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *nonceHeap) Push(x any) {
+	*h = append(*h, x.(nonceEntry))
+}
+
+func (h *nonceHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	*h = old[:n-1]
+	return e
+}
@@ -4,11 +4,17 @@
 //
 // Go representation of JobResult.
 // Signs result with HMAC-SHA256 (shared secret).
+//
+// Signature v1 is exactly the field set TS's signer covers: jobId, status,
+// startedAt, finishedAt, resultHash, traceId, workerId and metrics. v2
+// (sent as signatureVersion: 2, which TS must verify accordingly) also
+// covers errorCode, errorMessage, the giveUp/retryAfterMs hints, the
+// truncated flag and signatureVersion itself. resultData is covered
+// through resultHash in both.
 
 package contracts
 
 import (
-	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -25,12 +31,28 @@ type JobResult struct {
 	ResultData   any        `json:"resultData,omitempty"`
 	ErrorCode    string     `json:"errorCode,omitempty"`
 	ErrorMessage string     `json:"errorMessage,omitempty"`
+	RetryAfterMs int64      `json:"retryAfterMs,omitempty"` // suggested delay before retry (FAILED only)
+	GiveUp       bool       `json:"giveUp,omitempty"`       // worker suggests no further retries
+	Truncated    bool       `json:"truncated,omitempty"`    // v2 only: ResultData is a summary; ResultHash covers the full data
 	Metrics      JobMetrics `json:"metrics"`
 	TraceID      string     `json:"traceId"`
 	WorkerID     string     `json:"workerId"`
-	Signature    string     `json:"signature"`
+
+	// Field set covered by Signature (set by Sign; omitted for v1)
+	SignatureVersion int    `json:"signatureVersion,omitempty"`
+	Signature        string `json:"signature"`
 }
 
+// Result signature versions (see the file comment).
+const (
+	ResultSignatureV1 = 1
+	ResultSignatureV2 = 2
+)
+
+// ResultSignatureVersion is the version Sign produces. Set from
+// RESULT_SIGNATURE_VERSION at startup once TS verifies that version.
+var ResultSignatureVersion = ResultSignatureV1
+
 // JobMetrics contains execution performance data.
 // Fields MUST be in alphabetical order by JSON tag
 // to match TS canonicalJSON recursive sort.
@@ -41,19 +63,56 @@ type JobMetrics struct {
 
 // resultSignableData is the structure used for HMAC computation.
 // Keys are sorted alphabetically to match TS canonical JSON.
+// The v2-only fields are always omitted under v1, and under v2 when unset.
 type resultSignableData struct {
-	FinishedAt int64      `json:"finishedAt"`
-	JobID      string     `json:"jobId"`
-	Metrics    JobMetrics `json:"metrics"`
-	ResultHash string     `json:"resultHash"`
-	StartedAt  int64      `json:"startedAt"`
-	Status     string     `json:"status"`
-	TraceID    string     `json:"traceId"`
-	WorkerID   string     `json:"workerId"`
+	ErrorCode        string     `json:"errorCode,omitempty"`
+	ErrorMessage     string     `json:"errorMessage,omitempty"`
+	FinishedAt       int64      `json:"finishedAt"`
+	GiveUp           bool       `json:"giveUp,omitempty"`
+	JobID            string     `json:"jobId"`
+	Metrics          JobMetrics `json:"metrics"`
+	ResultHash       string     `json:"resultHash"`
+	RetryAfterMs     int64      `json:"retryAfterMs,omitempty"`
+	SignatureVersion int        `json:"signatureVersion,omitempty"`
+	StartedAt        int64      `json:"startedAt"`
+	Status           string     `json:"status"`
+	TraceID          string     `json:"traceId"`
+	Truncated        bool       `json:"truncated,omitempty"`
+	WorkerID         string     `json:"workerId"`
 }
 
-// Sign computes the HMAC-SHA256 signature for this result.
+// Sign computes the HMAC-SHA256 signature for this result, over the
+// ResultSignatureVersion field set.
 func (r *JobResult) Sign(secret string) error {
+	r.SignatureVersion = 0
+	if ResultSignatureVersion >= ResultSignatureV2 {
+		r.SignatureVersion = ResultSignatureVersion
+	}
+	signable, err := r.signableData()
+	if err != nil {
+		return err
+	}
+
+	r.Signature = ComputeHMAC(secret, signable)
+
+	return nil
+}
+
+// Verify checks Signature over the field set of the result's own
+// SignatureVersion, the way TS does.
+func (r *JobResult) Verify(secret string) error {
+	signable, err := r.signableData()
+	if err != nil {
+		return err
+	}
+	if err := VerifyHMAC(secret, signable, r.Signature); err != nil {
+		return fmt.Errorf("invalid result signature: %w", err)
+	}
+	return nil
+}
+
+// signableData returns the canonical JSON covered by Signature.
+func (r *JobResult) signableData() (string, error) {
 	signable := resultSignableData{
 		FinishedAt: r.FinishedAt,
 		JobID:      r.JobID,
@@ -64,25 +123,45 @@ func (r *JobResult) Sign(secret string) error {
 		TraceID:    r.TraceID,
 		WorkerID:   r.WorkerID,
 	}
+	if r.SignatureVersion >= ResultSignatureV2 {
+		signable.ErrorCode = r.ErrorCode
+		signable.ErrorMessage = r.ErrorMessage
+		signable.GiveUp = r.GiveUp
+		signable.RetryAfterMs = r.RetryAfterMs
+		signable.SignatureVersion = r.SignatureVersion
+		signable.Truncated = r.Truncated
+	}
 
 	b, err := json.Marshal(signable)
 	if err != nil {
-		return fmt.Errorf("failed to marshal signable data: %w", err)
+		return "", fmt.Errorf("failed to marshal signable data: %w", err)
 	}
-
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(b)
-	r.Signature = hex.EncodeToString(mac.Sum(nil))
-
-	return nil
+	return string(b), nil
 }
 
 // ComputeResultHash computes SHA-256 hash of result data.
 func ComputeResultHash(data any) (string, error) {
+	_, hash, err := EncodeResultData(data)
+	return hash, err
+}
+
+// EncodeResultData returns the JSON encoding of result data and its
+// SHA-256 hash (the bytes a chunked upload sends).
+func EncodeResultData(data any) ([]byte, string, error) {
 	b, err := json.Marshal(data)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal result data: %w", err)
+		return nil, "", fmt.Errorf("failed to marshal result data: %w", err)
 	}
 	h := sha256.Sum256(b)
-	return hex.EncodeToString(h[:]), nil
+	return b, hex.EncodeToString(h[:]), nil
+}
+
+// TruncatedResultSummary is the ResultData posted in place of result data
+// over MAX_RESULT_BYTES.
+func TruncatedResultSummary(originalBytes, limitBytes int) map[string]any {
+	return map[string]any{
+		"truncated":     true,
+		"originalBytes": originalBytes,
+		"limitBytes":    limitBytes,
+	}
 }
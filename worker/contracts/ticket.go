@@ -11,12 +11,19 @@ import (
 	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 )
 
+// ClockSkewMs is the tolerated clock difference (ms) between this worker and
+// TS when validating ticket timestamps. Set from CLOCK_SKEW_MS at startup.
+var ClockSkewMs int64
+
 // JobTicket represents a signed job authorization from TS Core OS.
 type JobTicket struct {
 	JobID            string   `json:"jobId"`
@@ -46,9 +53,22 @@ type ticketSignableData struct {
 	TraceID          string   `json:"traceId"`
 }
 
+// ErrScopeOrder is returned by VerifySignature when the signature only
+// matches with the ticket's scopes sorted, i.e. TS sorted them to sign but
+// sent them in another order.
+var ErrScopeOrder = errors.New("invalid Ed25519 signature: it matches only with scope sorted; TS must send scope in the order it was signed")
+
 // GetSignableData returns the canonical JSON for signature verification.
-// Keys are sorted alphabetically to match TS canonical JSON.
+// Keys are sorted alphabetically to match TS canonical JSON. Scope is an
+// array, so its order is part of the signed data: it is signed exactly as
+// it appears in the ticket, and TS must not reorder it between signing and
+// sending (sorted is recommended).
 func (t *JobTicket) GetSignableData() (string, error) {
+	return t.signableData(t.Scope)
+}
+
+// signableData is GetSignableData with the given scope list.
+func (t *JobTicket) signableData(scope []string) (string, error) {
 	data := ticketSignableData{
 		ActorID:          t.ActorID,
 		ExpiresAt:        t.ExpiresAt,
@@ -58,7 +78,7 @@ func (t *JobTicket) GetSignableData() (string, error) {
 		PayloadHash:      t.PayloadHash,
 		PolicyDecisionID: t.PolicyDecisionID,
 		RequestedAt:      t.RequestedAt,
-		Scope:            t.Scope,
+		Scope:            scope,
 		TraceID:          t.TraceID,
 	}
 
@@ -86,26 +106,145 @@ func (t *JobTicket) VerifySignature(publicKeyBytes []byte) error {
 	}
 
 	if !ed25519.Verify(ed25519.PublicKey(publicKeyBytes), []byte(signable), sigBytes) {
+		if t.signedWithSortedScope(publicKeyBytes, sigBytes) {
+			return fmt.Errorf("%w (scope %v)", ErrScopeOrder, t.Scope)
+		}
 		return fmt.Errorf("invalid Ed25519 signature")
 	}
 
 	return nil
 }
 
-// ValidateExpiry checks that the ticket has not expired.
-func (t *JobTicket) ValidateExpiry() error {
-	now := time.Now().UnixMilli()
-	if t.ExpiresAt <= now {
-		return fmt.Errorf("ticket expired at %d, current time %d", t.ExpiresAt, now)
+// signedWithSortedScope reports whether an unsorted scope list is the only
+// reason the signature failed, for a clearer error than a bare mismatch.
+func (t *JobTicket) signedWithSortedScope(publicKeyBytes, sigBytes []byte) bool {
+	if slices.IsSorted(t.Scope) {
+		return false
+	}
+	sorted := slices.Clone(t.Scope)
+	slices.Sort(sorted)
+	signable, err := t.signableData(sorted)
+	return err == nil && ed25519.Verify(ed25519.PublicKey(publicKeyBytes), []byte(signable), sigBytes)
+}
+
+// ValidateFields checks that the identifying fields are set and PayloadHash
+// is a 64-char hex SHA-256, so a TS bug surfaces as a malformed ticket
+// instead of a confusing hash mismatch (or a spurious match on "").
+func (t *JobTicket) ValidateFields() error {
+	for _, f := range []struct{ name, value string }{
+		{"jobId", t.JobID},
+		{"jobType", t.JobType},
+		{"traceId", t.TraceID},
+	} {
+		if f.value == "" {
+			return fmt.Errorf("ticket %s is empty", f.name)
+		}
+	}
+	if len(t.PayloadHash) != 2*sha256.Size {
+		return fmt.Errorf("ticket payloadHash must be %d hex chars, got %d", 2*sha256.Size, len(t.PayloadHash))
+	}
+	if _, err := hex.DecodeString(t.PayloadHash); err != nil {
+		return fmt.Errorf("ticket payloadHash is not hex: %w", err)
+	}
+	return nil
+}
+
+// ValidateNonce checks that the nonce decodes to at least minBytes, so
+// replay protection rests on enough entropy. TS sends randomUUID() strings,
+// which count as their 16 raw bytes; anything else must be base64
+// (standard or URL alphabet, padded or not). minBytes <= 0 skips the check.
+func (t *JobTicket) ValidateNonce(minBytes int) error {
+	if minBytes <= 0 {
+		return nil
+	}
+	if raw, ok := uuidBytes(t.Nonce); ok {
+		if len(raw) < minBytes {
+			return fmt.Errorf("ticket nonce is a UUID (%d bytes), need at least %d", len(raw), minBytes)
+		}
+		return nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		raw, err := enc.DecodeString(t.Nonce)
+		if err != nil {
+			continue
+		}
+		if len(raw) < minBytes {
+			return fmt.Errorf("ticket nonce decodes to %d bytes, need at least %d", len(raw), minBytes)
+		}
+		return nil
+	}
+	return fmt.Errorf("ticket nonce is neither a UUID nor base64")
+}
+
+// uuidBytes decodes a canonical 8-4-4-4-12 hex UUID string.
+func uuidBytes(s string) ([]byte, bool) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return nil, false
+	}
+	raw, err := hex.DecodeString(s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:])
+	return raw, err == nil
+}
+
+// ValidateExpiry checks that the ticket had not expired and was not issued
+// in the future at now, allowing ClockSkewMs of tolerance in both directions.
+func (t *JobTicket) ValidateExpiry(now time.Time) error {
+	nowMs := now.UnixMilli()
+	if t.ExpiresAt+ClockSkewMs <= nowMs {
+		return fmt.Errorf("ticket expired at %d, current time %d (skew tolerance %dms)", t.ExpiresAt, nowMs, ClockSkewMs)
+	}
+	if t.RequestedAt > nowMs+ClockSkewMs {
+		return fmt.Errorf("ticket requestedAt %d is in the future, current time %d (skew tolerance %dms)", t.RequestedAt, nowMs, ClockSkewMs)
+	}
+	return nil
+}
+
+// ValidateAge checks that the ticket was requested no more than maxAge
+// before now (0 = no limit), so stale queued jobs can be dropped instead of
+// run.
+func (t *JobTicket) ValidateAge(now time.Time, maxAge time.Duration) error {
+	if maxAge <= 0 {
+		return nil
+	}
+	age := now.Sub(time.UnixMilli(t.RequestedAt))
+	if age > maxAge {
+		return fmt.Errorf("job requested %s ago, max age is %s", age.Round(time.Second), maxAge)
+	}
+	return nil
+}
+
+// Deadline returns the latest time work under this ticket may run, including
+// the ClockSkewMs tolerance ValidateExpiry allows.
+func (t *JobTicket) Deadline() time.Time {
+	return time.UnixMilli(t.ExpiresAt + ClockSkewMs)
+}
+
+// ValidateScope checks that the ticket grants every required scope.
+func (t *JobTicket) ValidateScope(required []string) error {
+	for _, need := range required {
+		if !slices.Contains(t.Scope, need) {
+			return fmt.Errorf("ticket scope %v is missing required scope %q", t.Scope, need)
+		}
 	}
 	return nil
 }
 
-// ValidatePayloadHash verifies that the payload hash matches.
+// HashMismatchError reports a hash that differs from the signed one.
+type HashMismatchError struct {
+	Expected string // hash from the signed ticket or result
+	Computed string // hash computed by the worker
+}
+
+// Error implements error.
+func (e *HashMismatchError) Error() string {
+	return fmt.Sprintf("payload hash mismatch: expected %s, computed %s", e.Expected, e.Computed)
+}
+
+// ValidatePayloadHash verifies that the payload hash matches. A mismatch
+// is returned as a *HashMismatchError.
 func (t *JobTicket) ValidatePayloadHash(payload string) error {
 	computed := ComputePayloadHash(payload)
 	if t.PayloadHash != computed {
-		return fmt.Errorf("payload hash mismatch: expected %s, got %s", computed, t.PayloadHash)
+		return &HashMismatchError{Expected: t.PayloadHash, Computed: computed}
 	}
 	return nil
 }
@@ -0,0 +1,94 @@
+package contracts
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/clock"
+)
+
+func TestValidateExpiryFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.UnixMilli(1_700_000_000_000))
+	ticket := JobTicket{
+		RequestedAt: fake.Now().UnixMilli(),
+		ExpiresAt:   fake.Now().Add(time.Minute).UnixMilli(),
+	}
+
+	if err := ticket.ValidateExpiry(fake.Now()); err != nil {
+		t.Fatalf("fresh ticket: ValidateExpiry() = %v", err)
+	}
+	fake.Advance(time.Minute - time.Millisecond)
+	if err := ticket.ValidateExpiry(fake.Now()); err != nil {
+		t.Fatalf("just before expiry: ValidateExpiry() = %v", err)
+	}
+	fake.Advance(time.Millisecond)
+	if err := ticket.ValidateExpiry(fake.Now()); err == nil {
+		t.Fatal("at expiry: ValidateExpiry() = nil, want error")
+	}
+
+	fake.Set(time.UnixMilli(ticket.RequestedAt).Add(-time.Second))
+	if err := ticket.ValidateExpiry(fake.Now()); err == nil {
+		t.Fatal("requested in the future: ValidateExpiry() = nil, want error")
+	}
+}
+
+func TestValidateExpiryClockSkew(t *testing.T) {
+	defer func(old int64) { ClockSkewMs = old }(ClockSkewMs)
+	ClockSkewMs = 5000
+
+	fake := clock.NewFake(time.UnixMilli(1_700_000_000_000))
+	ticket := JobTicket{
+		RequestedAt: fake.Now().Add(4 * time.Second).UnixMilli(), // TS clock slightly ahead
+		ExpiresAt:   fake.Now().Add(time.Minute).UnixMilli(),
+	}
+	if err := ticket.ValidateExpiry(fake.Now()); err != nil {
+		t.Fatalf("within skew: ValidateExpiry() = %v", err)
+	}
+	fake.Advance(time.Minute + 4*time.Second)
+	if err := ticket.ValidateExpiry(fake.Now()); err != nil {
+		t.Fatalf("expired within skew: ValidateExpiry() = %v", err)
+	}
+	fake.Advance(time.Second)
+	if err := ticket.ValidateExpiry(fake.Now()); err == nil {
+		t.Fatal("expired beyond skew: ValidateExpiry() = nil, want error")
+	}
+}
+
+func TestValidateAgeFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.UnixMilli(1_700_000_000_000))
+	ticket := JobTicket{RequestedAt: fake.Now().UnixMilli()}
+
+	fake.Advance(10 * time.Minute)
+	if err := ticket.ValidateAge(fake.Now(), 0); err != nil {
+		t.Fatalf("no limit: ValidateAge() = %v", err)
+	}
+	if err := ticket.ValidateAge(fake.Now(), 10*time.Minute); err != nil {
+		t.Fatalf("at the limit: ValidateAge() = %v", err)
+	}
+	fake.Advance(time.Second)
+	if err := ticket.ValidateAge(fake.Now(), 10*time.Minute); err == nil {
+		t.Fatal("past the limit: ValidateAge() = nil, want error")
+	}
+}
+
+func TestNonceCacheFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.UnixMilli(1_700_000_000_000))
+	cache := NewNonceCache(10)
+	expiresAt := fake.Now().Add(time.Minute).UnixMilli()
+
+	if err := cache.CheckAndStore("nonce-1", expiresAt, fake.Now()); err != nil {
+		t.Fatalf("first use: CheckAndStore() = %v", err)
+	}
+	fake.Advance(30 * time.Second)
+	if err := cache.CheckAndStore("nonce-1", expiresAt, fake.Now()); !errors.Is(err, ErrNonceReplay) {
+		t.Fatalf("replay before expiry: CheckAndStore() = %v, want ErrNonceReplay", err)
+	}
+	fake.Advance(30 * time.Second)
+	if err := cache.CheckAndStore("nonce-2", expiresAt, fake.Now()); err != nil {
+		t.Fatalf("after expiry: CheckAndStore() = %v", err)
+	}
+	if got := cache.Len(); got != 1 {
+		t.Fatalf("Len() = %d after expiry, want 1 (nonce-1 evicted)", got)
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/gemimi2525-star/super-platform/worker
 
 go 1.22
+
+require golang.org/x/time v0.5.0
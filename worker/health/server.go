@@ -0,0 +1,122 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Worker Health Server (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Optional HTTP server for orchestrator probes.
+// /healthz — liveness (poll loop is ticking), plus the worker build version
+// /readyz  — readiness (first successful claim round-trip to TS); "starting"
+//            during STARTUP_GRACE_SECONDS
+// /metrics — Prometheus metrics (registered by main when METRICS_ENABLED=true)
+// /debug/jobs — in-flight jobs as JSON (registered by main when DEBUG_ENABLED=true)
+// /debug/handlers — registered jobTypes as JSON (likewise)
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/version"
+)
+
+// Checker reports worker liveness and readiness.
+type Checker interface {
+	Alive() bool
+	Ready() bool
+}
+
+// StartupChecker is a Checker that can also report it is still starting
+// up, so /readyz can say so rather than plain "unavailable".
+type StartupChecker interface {
+	Checker
+	Starting() bool
+}
+
+// Server serves health probes (and any extra handlers registered on it).
+type Server struct {
+	mux     *http.ServeMux
+	server  *http.Server
+	checker Checker
+	logger  *slog.Logger
+}
+
+// NewServer creates a health server listening on the given port.
+func NewServer(port int, checker Checker, logger *slog.Logger) *Server {
+	s := &Server{
+		mux:     http.NewServeMux(),
+		checker: checker,
+		logger:  logger,
+	}
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
+	s.mux.HandleFunc("/readyz", s.handleReadyz)
+
+	s.server = &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           s.mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Handle registers an additional handler on the health server.
+// Must be called before Run.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Run serves until ctx is cancelled, then shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Info("health server listening", "addr", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("health server shutdown: %w", err)
+	}
+	s.logger.Info("health server stopped")
+	return nil
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, s.checker.Alive())
+	fmt.Fprintf(w, "version %s\n", version.String())
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if sc, ok := s.checker.(StartupChecker); ok && sc.Starting() {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "starting")
+		return
+	}
+	writeProbe(w, s.checker.Ready())
+}
+
+// writeProbe writes a plain-text probe response.
+func writeProbe(w http.ResponseWriter, ok bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "unavailable")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
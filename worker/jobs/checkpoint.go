@@ -0,0 +1,56 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Handler Checkpoints (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Handlers for long jobs can be registered with RegisterCheckpointing to
+// receive a Checkpoint callback. The worker posts the latest checkpoint to
+// TS with each heartbeat; on a retry TS passes it back in the payload
+// ("checkpoint") so the handler can resume instead of starting over.
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+)
+
+// Checkpoint records a handler's progress. data must be JSON-encodable;
+// only the latest checkpoint between two heartbeats is sent, and a failed
+// send never fails the job.
+type Checkpoint func(data any)
+
+// CheckpointHandler is a JobHandler that can emit progress checkpoints.
+type CheckpointHandler func(ctx context.Context, payload string, traceID string, checkpoint Checkpoint) (resultData any, err error)
+
+// RegisterCheckpointing adds a checkpointing handler for a jobType, with
+// the same rules as Register.
+func (d *Dispatcher) RegisterCheckpointing(jobType string, handler CheckpointHandler) error {
+	if handler == nil {
+		return fmt.Errorf("invalid handler registration for jobType %q", jobType)
+	}
+	if err := d.Register(jobType, withoutCheckpoints(handler)); err != nil {
+		return err
+	}
+	d.checkpointers[jobType] = handler
+	return nil
+}
+
+// DispatchWithCheckpoint is Dispatch, passing checkpoint to handlers
+// registered with RegisterCheckpointing.
+func (d *Dispatcher) DispatchWithCheckpoint(ctx context.Context, jobType string, payload string, traceID string, checkpoint Checkpoint) (any, error) {
+	handler, ok := d.checkpointers[jobType]
+	if !ok || checkpoint == nil {
+		return d.Dispatch(ctx, jobType, payload, traceID)
+	}
+	return d.dispatch(ctx, jobType, traceID, func() (any, error) {
+		return handler(ctx, payload, traceID, checkpoint)
+	})
+}
+
+// withoutCheckpoints adapts a CheckpointHandler to a JobHandler that
+// discards its checkpoints.
+func withoutCheckpoints(handler CheckpointHandler) JobHandler {
+	return func(ctx context.Context, payload string, traceID string) (any, error) {
+		return handler(ctx, payload, traceID, func(any) {})
+	}
+}
@@ -3,54 +3,181 @@
 // ═══════════════════════════════════════════════════════════════════════════
 //
 // Routes jobType to the correct handler.
-// Includes __test.fail_n_times for smoke testing retry/dead-letter.
+// Includes __test.fail_n_times for smoke testing retry/dead-letter, and
+// __test.echo (opt-in) for canary round-trips.
 
 package jobs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+	"github.com/gemimi2525-star/super-platform/worker/version"
 )
 
-// JobHandler processes a job and returns result data.
-type JobHandler func(payload string, traceID string) (resultData any, err error)
+// MetricDispatch counts handler invocations by jobType and result
+// ("ok", "error" or "unknown" for an unregistered jobType).
+const MetricDispatch = "worker_dispatch_total"
+
+// JobHandler processes a job and returns result data. ctx is cancelled when
+// the ticket expires or the lease is lost; long-running handlers should
+// return ctx.Err() promptly once it is done.
+type JobHandler func(ctx context.Context, payload string, traceID string) (resultData any, err error)
 
 // Dispatcher routes jobType to handlers.
 type Dispatcher struct {
 	handlers map[string]JobHandler
+	policies map[string]RetryPolicy
+	scopes   map[string][]string
+	schemas  map[string]PayloadSchema
+	versions map[string]string
+	logger   *slog.Logger
+	metrics  metrics.Metrics
+
+	// checkpointers holds handlers registered with RegisterCheckpointing
+	// (also in handlers, adapted)
+	checkpointers map[string]CheckpointHandler
+
+	// testTypes lists __test.* jobTypes in RegisteredTypes
+	testTypes bool
 }
 
-// NewDispatcher creates a dispatcher with all registered job handlers.
-func NewDispatcher() *Dispatcher {
+// NewDispatcher creates a dispatcher with all registered job handlers
+// and their retry policies, required scopes and payload schemas.
+// Built-in handlers are unversioned (see SetVersion).
+func NewDispatcher(logger *slog.Logger) *Dispatcher {
 	d := &Dispatcher{
-		handlers: make(map[string]JobHandler),
+		handlers:      make(map[string]JobHandler),
+		checkpointers: make(map[string]CheckpointHandler),
+		policies:      make(map[string]RetryPolicy),
+		scopes:        make(map[string][]string),
+		schemas:       make(map[string]PayloadSchema),
+		versions:      make(map[string]string),
+		logger:        logger,
+		metrics:       metrics.Nop,
 	}
 
-	d.Register("scheduler.tick", HandleSchedulerTick)
-	d.Register("index.build", HandleIndexBuild)
-	d.Register("webhook.process", HandleWebhookProcess)
-	d.Register("__test.fail_n_times", HandleTestFailNTimes)
-	d.Register("__test.hang", HandleTestHang)
+	d.handlers["scheduler.tick"] = HandleSchedulerTick
+	d.RegisterCheckpointing("index.build", HandleIndexBuild)
+	d.handlers["webhook.process"] = HandleWebhookProcess
+	d.handlers["http.request"] = HandleHTTPRequest
+	d.handlers["__test.fail_n_times"] = HandleTestFailNTimes
+	d.handlers["__test.hang"] = HandleTestHang
+
+	// index.build is expensive — fail fast; webhook.process retries quickly
+	d.SetPolicy("index.build", RetryPolicy{MaxAttempts: 1})
+	d.SetPolicy("webhook.process", RetryPolicy{RetryAfter: 5 * time.Second})
+
+	// Production jobTypes need the default "execute" grant TS issues
+	d.SetRequiredScope("scheduler.tick", "execute")
+	d.SetRequiredScope("index.build", "execute")
+	d.SetRequiredScope("webhook.process", "execute")
+	d.SetRequiredScope("http.request", "execute")
+
+	d.SetSchema("http.request", httpRequestSchema)
+	d.SetSchema("__test.fail_n_times", PayloadSchema{Fields: []Field{
+		{Name: "reason", Type: TypeString},
+		{Name: "failCount", Type: TypeInteger, Required: true},
+		{Name: "attempt", Type: TypeInteger},
+	}})
+	d.SetSchema("__test.hang", PayloadSchema{Fields: []Field{
+		{Name: "hangSec", Type: TypeInteger},
+	}})
 
 	return d
 }
 
 // Register adds a handler for a jobType.
-func (d *Dispatcher) Register(jobType string, handler JobHandler) {
+// Returns an error if the jobType already has a handler.
+// Must be called before the worker starts dispatching.
+func (d *Dispatcher) Register(jobType string, handler JobHandler) error {
+	if jobType == "" || handler == nil {
+		return fmt.Errorf("invalid handler registration for jobType %q", jobType)
+	}
+	if _, exists := d.handlers[jobType]; exists {
+		return fmt.Errorf("handler already registered for jobType: %s", jobType)
+	}
 	d.handlers[jobType] = handler
+	return nil
+}
+
+// JobTypes returns the registered jobTypes, sorted.
+func (d *Dispatcher) JobTypes() []string {
+	types := make([]string, 0, len(d.handlers))
+	for jobType := range d.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// RegisteredTypes returns the jobTypes this worker offers, sorted: JobTypes
+// without the __test.* smoke-test handlers unless IncludeTestTypes was called.
+func (d *Dispatcher) RegisteredTypes() []string {
+	types := d.JobTypes()
+	if d.testTypes {
+		return types
+	}
+	return slices.DeleteFunc(types, func(jobType string) bool {
+		return strings.HasPrefix(jobType, "__test.")
+	})
+}
+
+// IncludeTestTypes makes RegisteredTypes list the __test.* jobTypes
+// (ENABLE_TEST_HANDLERS=true).
+func (d *Dispatcher) IncludeTestTypes() {
+	d.testTypes = true
 }
 
 // Dispatch routes a job to its handler.
-func (d *Dispatcher) Dispatch(jobType string, payload string, traceID string) (any, error) {
+func (d *Dispatcher) Dispatch(ctx context.Context, jobType string, payload string, traceID string) (any, error) {
 	handler, ok := d.handlers[jobType]
 	if !ok {
+		d.metrics.IncrCounter(MetricDispatch, metrics.Labels{"jobType": jobType, "result": "unknown"})
 		return nil, fmt.Errorf("unknown jobType: %s", jobType)
 	}
 
-	log.Printf("[Dispatcher] Executing %s (trace=%s)", jobType, traceID)
-	return handler(payload, traceID)
+	return d.dispatch(ctx, jobType, traceID, func() (any, error) {
+		return handler(ctx, payload, traceID)
+	})
+}
+
+// dispatch runs a handler invocation, logging it and counting the outcome.
+func (d *Dispatcher) dispatch(ctx context.Context, jobType, traceID string, run func() (any, error)) (any, error) {
+	d.logger.Info("executing job", logging.KeyJobType, jobType, logging.KeyTraceID, traceID)
+	result, err := run()
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	d.metrics.IncrCounter(MetricDispatch, metrics.Labels{"jobType": jobType, "result": outcome})
+	return result, err
+}
+
+// SetMetrics sets where dispatch metrics are recorded (default: discarded).
+func (d *Dispatcher) SetMetrics(m metrics.Metrics) {
+	d.metrics = m
+}
+
+// Logger returns the default logger tagged for a handler invocation. Lines
+// logged through it are also captured for the job's result when
+// MAX_LOG_CAPTURE_BYTES is set (see logcapture.go), so custom handlers
+// should log through it too.
+func Logger(ctx context.Context, jobType, traceID string) *slog.Logger {
+	logger := slog.Default()
+	if capture := activeCapture(ctx); capture != nil {
+		logger = logging.Tee(logger, capture, slog.LevelInfo)
+	}
+	return logger.With(logging.KeyComponent, jobType, logging.KeyTraceID, traceID)
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -58,8 +185,8 @@ func (d *Dispatcher) Dispatch(jobType string, payload string, traceID string) (a
 // ═══════════════════════════════════════════════════════════════════════════
 
 // HandleSchedulerTick fires scheduled tasks.
-func HandleSchedulerTick(payload string, traceID string) (any, error) {
-	log.Printf("[scheduler.tick] Processing scheduled tick (trace=%s)", traceID)
+func HandleSchedulerTick(ctx context.Context, payload string, traceID string) (any, error) {
+	Logger(ctx, "scheduler.tick", traceID).Info("processing scheduled tick")
 
 	result := map[string]any{
 		"tickProcessed": true,
@@ -74,9 +201,27 @@ func HandleSchedulerTick(payload string, traceID string) (any, error) {
 // HANDLER: index.build
 // ═══════════════════════════════════════════════════════════════════════════
 
-// HandleIndexBuild runs background indexing.
-func HandleIndexBuild(payload string, traceID string) (any, error) {
-	log.Printf("[index.build] Building index (trace=%s)", traceID)
+// indexBuildPayload is the part of an index.build payload the stub reads.
+type indexBuildPayload struct {
+	// Checkpoint is the last checkpoint of a previous attempt, set by TS on retry
+	Checkpoint *indexBuildCheckpoint `json:"checkpoint"`
+}
+
+// indexBuildCheckpoint is the progress index.build checkpoints.
+type indexBuildCheckpoint struct {
+	Stage string `json:"stage"`
+}
+
+// HandleIndexBuild runs background indexing, checkpointing each stage.
+func HandleIndexBuild(ctx context.Context, payload string, traceID string, checkpoint Checkpoint) (any, error) {
+	logger := Logger(ctx, "index.build", traceID)
+	var p indexBuildPayload
+	json.Unmarshal([]byte(payload), &p)
+	if p.Checkpoint != nil {
+		logger.Info("resuming index build", "stage", p.Checkpoint.Stage)
+	}
+	logger.Info("building index")
+	checkpoint(indexBuildCheckpoint{Stage: "built"})
 
 	result := map[string]any{
 		"indexBuilt": true,
@@ -92,8 +237,8 @@ func HandleIndexBuild(payload string, traceID string) (any, error) {
 // ═══════════════════════════════════════════════════════════════════════════
 
 // HandleWebhookProcess handles generic webhook processing.
-func HandleWebhookProcess(payload string, traceID string) (any, error) {
-	log.Printf("[webhook.process] Processing webhook (trace=%s)", traceID)
+func HandleWebhookProcess(ctx context.Context, payload string, traceID string) (any, error) {
+	Logger(ctx, "webhook.process", traceID).Info("processing webhook")
 
 	result := map[string]any{
 		"webhookProcessed": true,
@@ -123,13 +268,13 @@ type testFailPayload struct {
 // NOTE: The "attempt" in payload is set at enqueue time and doesn't change.
 // We use the failCount to deterministically control behavior.
 // The actual attempt number comes from the envelope.
-func HandleTestFailNTimes(payload string, traceID string) (any, error) {
+func HandleTestFailNTimes(ctx context.Context, payload string, traceID string) (any, error) {
 	var p testFailPayload
 	if err := json.Unmarshal([]byte(payload), &p); err != nil {
 		return nil, fmt.Errorf("invalid __test.fail_n_times payload: %w", err)
 	}
 
-	log.Printf("[__test.fail_n_times] payload failCount=%d (trace=%s)", p.FailCount, traceID)
+	Logger(ctx, "__test.fail_n_times", traceID).Info("test failure handler", "failCount", p.FailCount)
 
 	// The TS side incremented attempts before dispatching to us.
 	// We always fail — the TS result route decides retry vs dead-letter.
@@ -152,8 +297,9 @@ type testHangPayload struct {
 	HangSec int `json:"hangSec"`
 }
 
-// HandleTestHang sleeps for hangSec seconds (default 300s) to simulate a stuck job.
-func HandleTestHang(payload string, traceID string) (any, error) {
+// HandleTestHang sleeps for hangSec seconds (default 300s) to simulate a stuck
+// job, returning early if ctx is cancelled.
+func HandleTestHang(ctx context.Context, payload string, traceID string) (any, error) {
 	var p testHangPayload
 	if err := json.Unmarshal([]byte(payload), &p); err != nil {
 		return nil, fmt.Errorf("invalid __test.hang payload: %w", err)
@@ -164,8 +310,16 @@ func HandleTestHang(payload string, traceID string) (any, error) {
 		duration = 300
 	}
 
-	log.Printf("[__test.hang] Sleeping %ds to simulate stuck job (trace=%s)", duration, traceID)
-	time.Sleep(time.Duration(duration) * time.Second)
+	logger := Logger(ctx, "__test.hang", traceID)
+	logger.Info("sleeping to simulate stuck job", "hangSec", duration)
+	timer := time.NewTimer(time.Duration(duration) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		logger.Info("stuck job cancelled", logging.KeyError, ctx.Err())
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
 
 	return map[string]any{
 		"hung":    true,
@@ -173,3 +327,27 @@ func HandleTestHang(payload string, traceID string) (any, error) {
 		"traceId": traceID,
 	}, nil
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// HANDLER: __test.echo (canary — ENABLE_TEST_HANDLERS=true only)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// NewTestEchoHandler returns a handler that echoes the payload verbatim with
+// worker metadata, so TS can confirm ticket verification and result signing
+// end-to-end on a fresh deployment without touching real data.
+func NewTestEchoHandler(workerID string) JobHandler {
+	return func(ctx context.Context, payload string, traceID string) (any, error) {
+		Logger(ctx, "__test.echo", traceID).Info("echoing canary payload", "bytes", len(payload))
+
+		return map[string]any{
+			"echo":    payload,
+			"traceId": traceID,
+			"worker": map[string]any{
+				"workerId":  workerID,
+				"version":   version.String(),
+				"goVersion": runtime.Version(),
+				"echoedAt":  time.Now().UnixMilli(),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,225 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — HTTP Request Handler (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// http.request performs the outbound HTTP call described by the payload
+// and returns the response status, headers and body as result data, so
+// simple integrations don't each need a custom handler.
+//
+// Guards: only http/https; targets resolving to loopback, private,
+// link-local, CGNAT or other non-public addresses are refused at dial time
+// (which also covers redirects and DNS rebinding) unless
+// ALLOW_PRIVATE_TARGETS=true; no proxy from the environment; a per-call
+// timeout; and a cap on the response body read.
+
+package jobs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+// http.request limits.
+const (
+	httpRequestDefaultTimeout = 10 * time.Second
+	httpRequestMaxTimeout     = 30 * time.Second
+	httpRequestMaxBodyBytes   = 1 << 20 // 1 MiB; longer bodies are truncated
+	httpRequestMaxRedirects   = 5
+)
+
+// httpRequestPayload is the expected payload for http.request.
+type httpRequestPayload struct {
+	Method    string            `json:"method"` // default GET
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	Body      string            `json:"body"`
+	TimeoutMs int               `json:"timeoutMs"` // default 10s, max 30s
+}
+
+// httpRequestSchema is registered for http.request in NewDispatcher.
+var httpRequestSchema = PayloadSchema{Fields: []Field{
+	{Name: "method", Type: TypeString},
+	{Name: "url", Type: TypeString, Required: true},
+	{Name: "headers", Type: TypeObject},
+	{Name: "body", Type: TypeString},
+	{Name: "timeoutMs", Type: TypeInteger},
+}}
+
+// errPrivateTarget is returned when a request would reach a non-public address.
+var errPrivateTarget = errors.New("target address is not public (set ALLOW_PRIVATE_TARGETS=true to allow)")
+
+// HandleHTTPRequest performs the payload's HTTP call, refusing private
+// targets. A 5xx response is returned as an error so the job is retried;
+// any other status is returned as result data.
+func HandleHTTPRequest(ctx context.Context, payload string, traceID string) (any, error) {
+	return publicHTTPClient.do(ctx, payload, traceID)
+}
+
+// AllowPrivateTargets lets http.request reach private and loopback
+// addresses (ALLOW_PRIVATE_TARGETS=true). Must be called before the
+// worker starts dispatching.
+func (d *Dispatcher) AllowPrivateTargets() {
+	d.handlers["http.request"] = privateHTTPClient.do
+}
+
+// httpRequester is an http.request client with a fixed target policy.
+type httpRequester struct {
+	client *http.Client
+}
+
+var (
+	publicHTTPClient  = newHTTPRequester(false)
+	privateHTTPClient = newHTTPRequester(true)
+)
+
+func newHTTPRequester(allowPrivate bool) *httpRequester {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = denyPrivateDial
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: httpRequestMaxTimeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &httpRequester{client: &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= httpRequestMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", httpRequestMaxRedirects)
+			}
+			return checkHTTPTarget(req.URL)
+		},
+	}}
+}
+
+func (h *httpRequester) do(ctx context.Context, payload string, traceID string) (any, error) {
+	var p httpRequestPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, fmt.Errorf("invalid http.request payload: %w", err)
+	}
+	target, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http.request url: %w", err)
+	}
+	if err := checkHTTPTarget(target); err != nil {
+		return nil, err
+	}
+	method := strings.ToUpper(p.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	timeout := httpRequestDefaultTimeout
+	if p.TimeoutMs > 0 {
+		timeout = min(time.Duration(p.TimeoutMs)*time.Millisecond, httpRequestMaxTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if p.Body != "" {
+		body = strings.NewReader(p.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http.request: %w", err)
+	}
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+
+	logger := Logger(ctx, "http.request", traceID)
+	logger.Info("sending HTTP request", "method", method, "host", target.Host)
+	start := time.Now()
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http.request %s %s: %w", method, target.Host, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, httpRequestMaxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("http.request %s %s: read response: %w", method, target.Host, err)
+	}
+	truncated := len(data) > httpRequestMaxBodyBytes
+	if truncated {
+		data = data[:httpRequestMaxBodyBytes]
+	}
+	latency := time.Since(start)
+	logger.Info("HTTP request completed", "status", resp.StatusCode, "bytes", len(data), "latencyMs", latency.Milliseconds())
+
+	if resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("http.request %s %s: upstream returned %d", method, target.Host, resp.StatusCode)
+	}
+
+	headers := make(map[string]string, len(resp.Header))
+	for k := range resp.Header {
+		headers[k] = resp.Header.Get(k)
+	}
+	result := map[string]any{
+		"status":     resp.StatusCode,
+		"headers":    headers,
+		"truncated":  truncated,
+		"durationMs": latency.Milliseconds(),
+		"traceId":    traceID,
+	}
+	if utf8.Valid(data) {
+		result["body"] = string(data)
+	} else {
+		result["bodyBase64"] = base64.StdEncoding.EncodeToString(data)
+	}
+	return result, nil
+}
+
+// checkHTTPTarget allows only absolute http/https URLs.
+func checkHTTPTarget(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("http.request url scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("http.request url has no host")
+	}
+	return nil
+}
+
+// denyPrivateDial is a net.Dialer Control hook: it runs on the resolved
+// address of every connection, so hostnames and redirects can't bypass it.
+func denyPrivateDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return errPrivateTarget
+	}
+	return nil
+}
+
+// cgnat is the shared address space (RFC 6598), not covered by IsPrivate.
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP reports whether ip is a globally routable unicast address.
+func publicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		if ip4[0] == 0 || cgnat.Contains(ip4) {
+			return false
+		}
+	}
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
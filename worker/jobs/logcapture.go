@@ -0,0 +1,103 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Handler Log Capture (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// MAX_LOG_CAPTURE_BYTES > 0: while a job runs, lines its handler logs
+// through Logger are also kept in a per-job buffer, and the last
+// MAX_LOG_CAPTURE_BYTES of them are attached to the result data under
+// "logs" (on FAILED results too). This gives per-job diagnostics without
+// scraping the worker's global log. The capture travels in the handler's
+// context, so concurrent jobs never share one.
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"sync"
+)
+
+// logCaptureKey is the context key for a job's *LogCapture.
+type logCaptureKey struct{}
+
+// LogCapture keeps the tail of a job's handler log lines. Safe for
+// concurrent use.
+type LogCapture struct {
+	mu      sync.Mutex
+	max     int
+	buf     []byte
+	dropped bool // older output was discarded to stay within max
+}
+
+// CaptureLogs returns a context under which lines logged through Logger
+// are also captured, keeping the last max bytes. Pass it to the handler.
+func CaptureLogs(ctx context.Context, max int) (context.Context, *LogCapture) {
+	capture := &LogCapture{max: max}
+	return context.WithValue(ctx, logCaptureKey{}, capture), capture
+}
+
+// activeCapture returns the capture carried by ctx, or nil.
+func activeCapture(ctx context.Context) *LogCapture {
+	capture, _ := ctx.Value(logCaptureKey{}).(*LogCapture)
+	return capture
+}
+
+// Write appends p, discarding the oldest output beyond max bytes (up to
+// a line boundary where there is one).
+func (c *LogCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buf = append(c.buf, p...)
+	if over := len(c.buf) - c.max; over > 0 {
+		if i := bytes.IndexByte(c.buf[over:len(c.buf)-1], '\n'); i >= 0 {
+			over += i + 1
+		}
+		c.buf = append(c.buf[:0], c.buf[over:]...)
+		c.dropped = true
+	}
+	return len(p), nil
+}
+
+// Tail returns the captured output, prefixed with "…" if older lines were
+// discarded ("" if nothing was logged).
+func (c *LogCapture) Tail() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dropped {
+		return "…" + string(c.buf)
+	}
+	return string(c.buf)
+}
+
+// AttachLogs returns resultData with logs added under "logs". Only nil and
+// JSON-object results can carry them; for any other result, or one that
+// already has a "logs" field, it returns resultData unchanged and false.
+func AttachLogs(resultData any, logs string) (any, bool) {
+	if logs == "" {
+		return resultData, true
+	}
+	if resultData == nil {
+		return map[string]any{"logs": logs}, true
+	}
+	fields, ok := resultData.(map[string]any)
+	if ok {
+		fields = maps.Clone(fields)
+	} else {
+		encoded, err := json.Marshal(resultData)
+		if err != nil {
+			return resultData, false
+		}
+		dec := json.NewDecoder(bytes.NewReader(encoded))
+		dec.UseNumber() // keep large integers exact
+		if err := dec.Decode(&fields); err != nil || fields == nil {
+			return resultData, false
+		}
+	}
+	if _, exists := fields["logs"]; exists {
+		return resultData, false
+	}
+	fields["logs"] = logs
+	return fields, true
+}
@@ -0,0 +1,55 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Retry Policies (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Per-jobType retry hints attached to FAILED results.
+// TS still owns the retry decision; these are suggestions it can honor.
+
+package jobs
+
+import "time"
+
+// RetryPolicy describes how failures of a jobType should be retried.
+type RetryPolicy struct {
+	// MaxAttempts caps attempts for this jobType (0 = use TS-assigned maxAttempts).
+	MaxAttempts int
+
+	// RetryAfter is the suggested delay before the next attempt (0 = TS default).
+	RetryAfter time.Duration
+}
+
+// DefaultRetryPolicy defers entirely to TS.
+var DefaultRetryPolicy = RetryPolicy{}
+
+// GiveUp reports whether the worker should suggest no further retries
+// after the given attempt number.
+func (p RetryPolicy) GiveUp(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+// Backoff returns base * 2^attempt capped at max, the retry delay
+// suggested for a failed attempt when its jobType sets no RetryAfter
+// (0 if base is 0).
+func Backoff(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
+
+// SetPolicy registers a retry policy for a jobType.
+func (d *Dispatcher) SetPolicy(jobType string, policy RetryPolicy) {
+	d.policies[jobType] = policy
+}
+
+// PolicyFor returns the retry policy for a jobType, or DefaultRetryPolicy.
+func (d *Dispatcher) PolicyFor(jobType string) RetryPolicy {
+	if p, ok := d.policies[jobType]; ok {
+		return p
+	}
+	return DefaultRetryPolicy
+}
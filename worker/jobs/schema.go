@@ -0,0 +1,104 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Payload Schemas (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Optional per-jobType payload validation, run before the handler so a
+// malformed payload fails fast with a field-level message.
+// jobTypes without a schema are passed to their handler unchecked.
+
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// FieldType is the JSON type expected for a payload field.
+type FieldType string
+
+// Supported field types.
+const (
+	TypeString  FieldType = "string"
+	TypeNumber  FieldType = "number"
+	TypeInteger FieldType = "integer"
+	TypeBoolean FieldType = "boolean"
+	TypeObject  FieldType = "object"
+	TypeArray   FieldType = "array"
+)
+
+// Field describes one top-level payload field.
+type Field struct {
+	Name     string
+	Type     FieldType
+	Required bool
+}
+
+// PayloadSchema describes the top-level fields of a JSON object payload.
+// Fields not listed are allowed.
+type PayloadSchema struct {
+	Fields []Field
+}
+
+// Validate checks a payload against the schema.
+func (s PayloadSchema) Validate(payload string) error {
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(payload), &obj); err != nil {
+		return fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+
+	for _, f := range s.Fields {
+		v, ok := obj[f.Name]
+		if !ok || v == nil {
+			if f.Required {
+				return fmt.Errorf("field %q is required", f.Name)
+			}
+			continue
+		}
+		if got := jsonType(v); !typeMatches(f.Type, v, got) {
+			return fmt.Errorf("field %q: expected %s, got %s", f.Name, f.Type, got)
+		}
+	}
+	return nil
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v any) FieldType {
+	switch v.(type) {
+	case string:
+		return TypeString
+	case float64:
+		return TypeNumber
+	case bool:
+		return TypeBoolean
+	case map[string]any:
+		return TypeObject
+	case []any:
+		return TypeArray
+	default:
+		return FieldType(fmt.Sprintf("%T", v))
+	}
+}
+
+// typeMatches reports whether v satisfies want (integers are whole numbers).
+func typeMatches(want FieldType, v any, got FieldType) bool {
+	if want == TypeInteger {
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	}
+	return want == got
+}
+
+// SetSchema registers a payload schema for a jobType.
+func (d *Dispatcher) SetSchema(jobType string, schema PayloadSchema) {
+	d.schemas[jobType] = schema
+}
+
+// ValidatePayload checks payload against the jobType's schema, if any.
+func (d *Dispatcher) ValidatePayload(jobType, payload string) error {
+	schema, ok := d.schemas[jobType]
+	if !ok {
+		return nil
+	}
+	return schema.Validate(payload)
+}
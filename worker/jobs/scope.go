@@ -0,0 +1,19 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Required Scopes (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Per-jobType scopes a ticket must carry before the job runs.
+// The ticket signature proves TS issued the scope; this checks it is enough.
+
+package jobs
+
+// SetRequiredScope registers the scopes a jobType's ticket must include.
+func (d *Dispatcher) SetRequiredScope(jobType string, scopes ...string) {
+	d.scopes[jobType] = scopes
+}
+
+// RequiredScope returns the scopes required for a jobType
+// (none for unknown jobTypes).
+func (d *Dispatcher) RequiredScope(jobType string) []string {
+	return d.scopes[jobType]
+}
@@ -0,0 +1,40 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Handler Versions (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Per-jobType handler versions. TS can pin a job to the handler version its
+// payload was written for (envelope handlerVersion), so a worker still on an
+// older handler rejects it instead of misreading a new-format payload during
+// a rollout. A job without handlerVersion runs on any version.
+
+package jobs
+
+import "fmt"
+
+// SetVersion registers the handler version a jobType's handler provides.
+func (d *Dispatcher) SetVersion(jobType, version string) {
+	d.versions[jobType] = version
+}
+
+// Version returns the handler version registered for a jobType
+// ("" if none).
+func (d *Dispatcher) Version(jobType string) string {
+	return d.versions[jobType]
+}
+
+// CheckVersion returns an error if a job requiring handler version
+// required can't run on this jobType's handler. An empty required
+// version matches any handler.
+func (d *Dispatcher) CheckVersion(jobType, required string) error {
+	if required == "" {
+		return nil
+	}
+	provided := d.versions[jobType]
+	if provided == required {
+		return nil
+	}
+	if provided == "" {
+		return fmt.Errorf("jobType %s requires handler version %s, this worker's handler is unversioned", jobType, required)
+	}
+	return fmt.Errorf("jobType %s requires handler version %s, this worker provides %s", jobType, required, provided)
+}
@@ -0,0 +1,174 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Worker Logging (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Structured logging backed by log/slog.
+// LOG_FORMAT=json for log aggregators, LOG_FORMAT=text for local dev.
+// LOG_SAMPLE_RATE=N thins routine lines to 1 in N per message; warnings
+// and errors are never sampled.
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Attribute keys shared by all worker components so log lines can be
+// correlated across Worker, Dispatcher and APIClient.
+const (
+	KeyComponent = "component"
+	KeyJobID     = "jobId"
+	KeyJobType   = "jobType"
+	KeyWorkerID  = "workerId"
+	KeyTraceID   = "traceId"
+	KeyAttempt   = "attempt"
+	KeyStatus    = "status"
+	KeyError     = "err"
+)
+
+// Supported LOG_FORMAT values.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel parses LOG_LEVEL (debug, info, warn, error).
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", s)
+	}
+	return level, nil
+}
+
+// ParseFormat validates LOG_FORMAT, defaulting to text.
+func ParseFormat(s string) (string, error) {
+	switch f := strings.ToLower(s); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid log format %q (expected json or text)", s)
+	}
+}
+
+// New creates a logger writing to w in the given format.
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Component returns a child logger tagged with a component name.
+func Component(logger *slog.Logger, name string) *slog.Logger {
+	return logger.With(KeyComponent, name)
+}
+
+// Dynamic returns a logger that adds key=value() to every record when it
+// is logged rather than when the logger is derived, for a value that can
+// still change after child loggers exist (the worker ID at registration).
+func Dynamic(logger *slog.Logger, key string, value func() string) *slog.Logger {
+	return slog.New(dynamicHandler{Handler: logger.Handler(), key: key, value: value})
+}
+
+type dynamicHandler struct {
+	slog.Handler
+	key   string
+	value func() string
+}
+
+func (h dynamicHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(slog.String(h.key, h.value()))
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h dynamicHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return dynamicHandler{Handler: h.Handler.WithAttrs(attrs), key: h.key, value: h.value}
+}
+
+func (h dynamicHandler) WithGroup(name string) slog.Handler {
+	return dynamicHandler{Handler: h.Handler.WithGroup(name), key: h.key, value: h.value}
+}
+
+// Sampled returns a logger that writes the first record of each message
+// below WARN and then every nth one, so per-job and per-heartbeat lines
+// thin out at high throughput while startup lines, warnings and errors
+// always get through. n <= 1 returns logger unchanged. The count is per
+// message text and shared by all loggers derived from the result.
+func Sampled(logger *slog.Logger, n int) *slog.Logger {
+	if n <= 1 {
+		return logger
+	}
+	return slog.New(sampledHandler{Handler: logger.Handler(), n: uint64(n), counts: &sync.Map{}})
+}
+
+type sampledHandler struct {
+	slog.Handler
+	n      uint64
+	counts *sync.Map // message → *atomic.Uint64
+}
+
+func (h sampledHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn {
+		c, _ := h.counts.LoadOrStore(r.Message, new(atomic.Uint64))
+		if (c.(*atomic.Uint64).Add(1)-1)%h.n != 0 {
+			return nil
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h sampledHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return sampledHandler{Handler: h.Handler.WithAttrs(attrs), n: h.n, counts: h.counts}
+}
+
+func (h sampledHandler) WithGroup(name string) slog.Handler {
+	return sampledHandler{Handler: h.Handler.WithGroup(name), n: h.n, counts: h.counts}
+}
+
+// Tee returns a logger that also writes records at level and above as
+// text lines to w, whatever logger's own level or sampling lets through
+// (e.g. a per-job log capture).
+func Tee(logger *slog.Logger, w io.Writer, level slog.Level) *slog.Logger {
+	tee := slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+	return slog.New(teeHandler{primary: logger.Handler(), tee: tee})
+}
+
+type teeHandler struct {
+	primary slog.Handler
+	tee     slog.Handler
+}
+
+func (h teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.primary.Enabled(ctx, level) || h.tee.Enabled(ctx, level)
+}
+
+func (h teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.tee.Enabled(ctx, r.Level) {
+		h.tee.Handle(ctx, r.Clone())
+	}
+	if !h.primary.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.primary.Handle(ctx, r)
+}
+
+func (h teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return teeHandler{primary: h.primary.WithAttrs(attrs), tee: h.tee.WithAttrs(attrs)}
+}
+
+func (h teeHandler) WithGroup(name string) slog.Handler {
+	return teeHandler{primary: h.primary.WithGroup(name), tee: h.tee.WithGroup(name)}
+}
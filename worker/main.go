@@ -2,20 +2,61 @@
 // CORE OS — Go Worker CLI Entrypoint (Phase 22A)
 // ═══════════════════════════════════════════════════════════════════════════
 //
-// Starts the worker polling loop.
-// Signal handling (SIGTERM/SIGINT) is done inside worker.Run().
+// Starts the worker polling loop and, if HEALTH_PORT is set, the health server.
+// Signal handling (SIGTERM/SIGINT, SIGHUP drain toggle or routing reload) is done inside worker.Run().
+// SIGUSR2 drains, then re-execs the binary at the same path with the same
+// args and env, so a rolling upgrade only has to replace the file.
+//
+// Flags:
+//   --check-config     validate configuration and TS reachability, then exit
+//   --run-file <path>  run one JobEnvelope JSON locally and print the result
+//   --replay <dir>     re-run envelopes recorded via RECORD_ENVELOPES_DIR locally
 
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
+	"os"
+	"syscall"
 
+	"github.com/gemimi2525-star/super-platform/worker/client"
 	"github.com/gemimi2525-star/super-platform/worker/config"
+	"github.com/gemimi2525-star/super-platform/worker/health"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/version"
 	"github.com/gemimi2525-star/super-platform/worker/worker"
 )
 
+// registerCustomHandlers is an optional hook for downstream forks to add
+// their own jobTypes: set it from an init() in a separate file, e.g.
+//
+//	func init() {
+//		registerCustomHandlers = func(w *worker.Worker) error {
+//			return w.RegisterHandler("acme.report", handleAcmeReport)
+//		}
+//	}
+var registerCustomHandlers func(w *worker.Worker) error
+
 func main() {
+	checkConfig := flag.Bool("check-config", false, "print effective config, validate it and exit")
+	runFilePath := flag.String("run-file", "", "run the JobEnvelope JSON at `path` locally, print the result and exit")
+	replayDir := flag.String("replay", "", "re-run the envelopes recorded in `dir` locally, print the results and exit")
+	flag.Parse()
+
+	if *checkConfig {
+		os.Exit(runCheckConfig())
+	}
+	if *runFilePath != "" {
+		os.Exit(runFile(*runFilePath))
+	}
+	if *replayDir != "" {
+		os.Exit(runReplay(*replayDir))
+	}
+
 	log.Println("═══════════════════════════════════════")
 	log.Println("  CORE OS — Go Worker (Phase 22A)")
 	log.Println("═══════════════════════════════════════")
@@ -25,18 +66,93 @@ func main() {
 	if err != nil {
 		log.Fatalf("[FATAL] Configuration error: %v", err)
 	}
-	log.Printf("[Config] API URL: %s", cfg.APIURL)
-	log.Printf("[Config] Worker ID: %s", cfg.WorkerID)
-	log.Printf("[Config] Poll interval: %s", cfg.PollInterval)
+
+	// Structured logging (also captures any remaining std log output)
+	logger := logging.Sampled(logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat), cfg.LogSampleRate)
+	slog.SetDefault(logger)
+
+	logger.Info("configuration loaded",
+		"version", version.String(),
+		"apiUrl", cfg.APIURL,
+		"standbyUrls", cfg.APIStandbyURLs,
+		logging.KeyWorkerID, cfg.WorkerID,
+		"pollInterval", cfg.PollInterval.String(),
+		"healthPort", cfg.HealthPort,
+		"metricsEnabled", cfg.MetricsEnabled,
+		"logLevel", cfg.LogLevel.String(),
+		"logFormat", cfg.LogFormat,
+		"logSampleRate", cfg.LogSampleRate)
 
 	// Create worker
-	w, err := worker.New(cfg)
+	w, err := worker.New(cfg, logger)
 	if err != nil {
-		log.Fatalf("[FATAL] Worker initialization failed: %v", err)
+		logger.Error("worker initialization failed", logging.KeyError, err)
+		os.Exit(1)
+	}
+
+	// Custom handlers (optional)
+	if registerCustomHandlers != nil {
+		if err := registerCustomHandlers(w); err != nil {
+			logger.Error("custom handler registration failed", logging.KeyError, err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Start health server (optional)
+	var healthDone chan struct{}
+	if cfg.HealthPort > 0 {
+		healthDone = make(chan struct{})
+		srv := health.NewServer(cfg.HealthPort, w, logging.Component(logger, "Health"))
+		if cfg.MetricsEnabled {
+			srv.Handle("/metrics", w.MetricsHandler())
+		}
+		if cfg.DebugEnabled {
+			srv.Handle("/debug/jobs", w.DebugJobsHandler())
+			srv.Handle("/debug/handlers", w.DebugHandlersHandler())
+		}
+		go func() {
+			defer close(healthDone)
+			if err := srv.Run(ctx); err != nil {
+				logger.Error("health server error", logging.KeyError, err)
+			}
+		}()
 	}
 
 	// Start polling loop (blocks until SIGTERM/SIGINT)
-	w.Run(context.Background())
+	runErr := w.Run(ctx)
 
-	log.Println("[Worker] Process exited.")
+	// Stop health server
+	cancel()
+	if healthDone != nil {
+		<-healthDone
+	}
+	if errors.Is(runErr, worker.ErrRestart) {
+		reexec(logger)
+	}
+	if errors.Is(runErr, client.ErrAuthFailed) {
+		logger.Error("worker stopped: TS rejected its credentials", logging.KeyError, runErr)
+		os.Exit(1)
+	}
+	if runErr != nil {
+		logger.Error("worker failed to start", logging.KeyError, runErr)
+		os.Exit(1)
+	}
+
+	logger.Info("process exited")
+}
+
+// reexec replaces the process with the binary now at its executable path.
+// Listeners are closed by then, and Go opens every fd close-on-exec, so
+// the new process inherits nothing but args and env.
+func reexec(logger *slog.Logger) {
+	exe, err := os.Executable()
+	if err == nil {
+		logger.Info("re-executing worker binary", "path", exe)
+		err = syscall.Exec(exe, os.Args, os.Environ())
+	}
+	logger.Error("re-exec failed", logging.KeyError, err)
+	os.Exit(1)
 }
@@ -0,0 +1,35 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Metrics Interface (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Worker, Dispatcher and APIClient record metrics through Metrics rather
+// than a specific library. Prometheus (this package's Registry) is the
+// built-in backend; a StatsD/Datadog adapter only needs these three
+// methods. Nop is the default where no backend is configured.
+
+package metrics
+
+// Labels are metric dimensions (Prometheus labels, StatsD/Datadog tags).
+type Labels map[string]string
+
+// Metrics records instrumentation. Implementations must be safe for
+// concurrent use.
+type Metrics interface {
+	// IncrCounter adds 1 to the counter name.
+	IncrCounter(name string, labels Labels)
+
+	// ObserveHistogram records one value of the distribution name.
+	ObserveHistogram(name string, value float64, labels Labels)
+
+	// SetGauge sets the current value of the gauge name.
+	SetGauge(name string, value float64, labels Labels)
+}
+
+// Nop discards all metrics.
+var Nop Metrics = nopMetrics{}
+
+type nopMetrics struct{}
+
+func (nopMetrics) IncrCounter(string, Labels)               {}
+func (nopMetrics) ObserveHistogram(string, float64, Labels) {}
+func (nopMetrics) SetGauge(string, float64, Labels)         {}
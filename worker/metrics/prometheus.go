@@ -0,0 +1,129 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Prometheus Metrics Adapter (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Implements Metrics on top of Registry. Families are declared up front
+// with help text, label names and buckets; a name that was never declared
+// is registered on first use with its labels taken from that call.
+
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Prometheus adapts a Registry to the Metrics interface.
+type Prometheus struct {
+	registry *Registry
+
+	mu         sync.Mutex
+	counters   map[string]*labelled[*Counter]
+	gauges     map[string]*labelled[*Gauge]
+	histograms map[string]*Histogram
+}
+
+// labelled pairs a family with the label names its values are ordered by.
+type labelled[T any] struct {
+	metric     T
+	labelNames []string
+}
+
+// NewPrometheus creates an adapter with an empty registry.
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		registry:   NewRegistry(),
+		counters:   make(map[string]*labelled[*Counter]),
+		gauges:     make(map[string]*labelled[*Gauge]),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// Handler serves the registry in Prometheus text format.
+func (p *Prometheus) Handler() http.Handler {
+	return p.registry
+}
+
+// DeclareCounter registers a counter family. Must be called before the
+// name is first used.
+func (p *Prometheus) DeclareCounter(name, help string, labelNames ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counters[name] = &labelled[*Counter]{p.registry.NewCounter(name, help, labelNames...), labelNames}
+}
+
+// DeclareGauge registers a gauge family. Must be called before the name is
+// first used.
+func (p *Prometheus) DeclareGauge(name, help string, labelNames ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.gauges[name] = &labelled[*Gauge]{p.registry.NewGauge(name, help, labelNames...), labelNames}
+}
+
+// DeclareHistogram registers a histogram with the given buckets. Registry
+// histograms are unlabelled, so labels passed to ObserveHistogram are
+// ignored.
+func (p *Prometheus) DeclareHistogram(name, help string, buckets []float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.histograms[name] = p.registry.NewHistogram(name, help, buckets)
+}
+
+// IncrCounter implements Metrics.
+func (p *Prometheus) IncrCounter(name string, labels Labels) {
+	p.mu.Lock()
+	c, ok := p.counters[name]
+	if !ok {
+		names := labelNames(labels)
+		c = &labelled[*Counter]{p.registry.NewCounter(name, name, names...), names}
+		p.counters[name] = c
+	}
+	p.mu.Unlock()
+	c.metric.Inc(labelValues(c.labelNames, labels)...)
+}
+
+// SetGauge implements Metrics.
+func (p *Prometheus) SetGauge(name string, value float64, labels Labels) {
+	p.mu.Lock()
+	g, ok := p.gauges[name]
+	if !ok {
+		names := labelNames(labels)
+		g = &labelled[*Gauge]{p.registry.NewGauge(name, name, names...), names}
+		p.gauges[name] = g
+	}
+	p.mu.Unlock()
+	g.metric.Set(value, labelValues(g.labelNames, labels)...)
+}
+
+// ObserveHistogram implements Metrics.
+func (p *Prometheus) ObserveHistogram(name string, value float64, _ Labels) {
+	p.mu.Lock()
+	h, ok := p.histograms[name]
+	if !ok {
+		h = p.registry.NewHistogram(name, name, DefaultLatencyBuckets)
+		p.histograms[name] = h
+	}
+	p.mu.Unlock()
+	h.Observe(value)
+}
+
+// labelNames returns the sorted keys of labels.
+func labelNames(labels Labels) []string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// labelValues orders labels by names; missing labels are empty and
+// undeclared ones are dropped.
+func labelValues(names []string, labels Labels) []string {
+	values := make([]string, len(names))
+	for i, n := range names {
+		values[i] = labels[n]
+	}
+	return values
+}
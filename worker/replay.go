@@ -0,0 +1,111 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Go Worker Envelope Replay (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// --replay <dir>: re-run envelopes recorded with RECORD_ENVELOPES_DIR, in
+// claim (file name) order, through the full ProcessJob pipeline with the
+// configured keys. Nothing is sent to TS. Each outcome is printed to
+// stdout as a JSON line with the signed result, if one was built. Ticket
+// expiry and age are checked as of the claim time in the file name, so an
+// envelope replays as it ran even after its ticket has expired (files not
+// named by the recorder are checked against the current time). Exit 0 if
+// every job succeeded, 1 otherwise.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+	"github.com/gemimi2525-star/super-platform/worker/worker"
+)
+
+// replayLine is one replayed envelope on stdout.
+type replayLine struct {
+	File      string               `json:"file"`
+	Status    string               `json:"status"`
+	ErrorCode string               `json:"errorCode,omitempty"`
+	Error     string               `json:"error,omitempty"`
+	Result    *contracts.JobResult `json:"result,omitempty"`
+}
+
+// runReplay replays every *.json envelope in dir and returns the process
+// exit code.
+func runReplay(dir string) int {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "replay: FAIL — no envelope files in %s\n", dir)
+		return 1
+	}
+	sort.Strings(files)
+
+	w, ok := newLocalWorker()
+	if !ok {
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	succeeded := 0
+	for _, path := range files {
+		line := replayLine{File: filepath.Base(path)}
+
+		var envelope client.JobEnvelope
+		raw, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(raw, &envelope)
+		}
+		if err != nil {
+			line.Status = "UNREADABLE"
+			line.Error = err.Error()
+			enc.Encode(line)
+			continue
+		}
+
+		var result *contracts.JobResult
+		var outcome worker.ProcessOutcome
+		if claimedAt, ok := recordedClaimTime(path); ok {
+			result, outcome, err = w.RunLocalAt(context.Background(), &envelope, claimedAt)
+		} else {
+			result, outcome, err = w.RunLocal(context.Background(), &envelope)
+		}
+		line.Status = outcome.Status
+		line.ErrorCode = outcome.ErrorCode
+		line.Result = result
+		if err != nil {
+			line.Error = err.Error()
+		}
+		if outcome.Status == worker.OutcomeSucceeded && err == nil {
+			succeeded++
+		}
+		enc.Encode(line)
+	}
+
+	fmt.Fprintf(os.Stderr, "replay: %d/%d envelopes succeeded\n", succeeded, len(files))
+	if succeeded != len(files) {
+		return 1
+	}
+	return 0
+}
+
+// recordedClaimTime parses the claim time from a recorder file name
+// (<claimedAtMs>-<jobId>-a<attempt>.json).
+func recordedClaimTime(path string) (time.Time, bool) {
+	prefix, _, found := strings.Cut(filepath.Base(path), "-")
+	if !found {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
@@ -0,0 +1,92 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Go Worker Local Run (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// --run-file <path>: read a JobEnvelope JSON from disk, run it through the
+// full ProcessJob pipeline with the configured keys, and print the signed
+// result JSON to stdout. Nothing is sent to TS. Exit 0 if the job
+// succeeded, 1 otherwise.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/config"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/worker"
+)
+
+// runFile processes one envelope file and returns the process exit code.
+func runFile(path string) int {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run-file: FAIL — %v\n", err)
+		return 1
+	}
+	var envelope client.JobEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		fmt.Fprintf(os.Stderr, "run-file: FAIL — invalid envelope JSON: %v\n", err)
+		return 1
+	}
+
+	w, ok := newLocalWorker()
+	if !ok {
+		return 1
+	}
+
+	result, outcome, err := w.RunLocal(context.Background(), &envelope)
+	if result != nil {
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(out))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run-file: FAIL — %v\n", err)
+		return 1
+	}
+	if outcome.Status != worker.OutcomeSucceeded {
+		fmt.Fprintf(os.Stderr, "run-file: job %s %s\n", outcome.Status, outcome.ErrorCode)
+		return 1
+	}
+	return 0
+}
+
+// newLocalWorker builds a worker for RunLocal from the environment, with
+// logs on stderr. Failures are printed; ok is false if there was one.
+func newLocalWorker() (w *worker.Worker, ok bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: FAIL — %v\n", err)
+		return nil, false
+	}
+	// No TS side effects: crash recovery would report WAL entries, and the
+	// audit log should only hold results TS accepted; local runs aren't
+	// traced, recorded or spooled
+	cfg.CrashRecovery = false
+	cfg.AuditEnabled = false
+	cfg.OTelEnabled = false
+	cfg.RecordEnvelopesDir = ""
+	cfg.ResultSpoolDir = ""
+
+	// Logs go to stderr so stdout holds only results
+	logger := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	slog.SetDefault(logger)
+
+	w, err = worker.New(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "worker init: FAIL — %v\n", err)
+		return nil, false
+	}
+	if registerCustomHandlers != nil {
+		if err := registerCustomHandlers(w); err != nil {
+			fmt.Fprintf(os.Stderr, "custom handler registration: FAIL — %v\n", err)
+			return nil, false
+		}
+	}
+	return w, true
+}
@@ -0,0 +1,301 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — OTLP/HTTP Span Exporter (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Batches finished spans and POSTs them to OTEL_EXPORTER_OTLP_ENDPOINT
+// + /v1/traces using the OTLP JSON encoding. Export is best-effort: a full
+// queue drops spans and failed posts are logged, never retried, so tracing
+// can't slow job processing.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// Export batching.
+const (
+	queueSize     = 2048
+	maxBatch      = 512
+	flushInterval = 5 * time.Second
+	exportTimeout = 10 * time.Second
+)
+
+// OTLP enum values.
+const (
+	spanKindConsumer = 5 // the worker consumes jobs enqueued by TS
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+// Tracer creates spans and exports them in the background.
+type Tracer struct {
+	url         string
+	serviceName string
+	httpClient  *http.Client
+	logger      *slog.Logger
+
+	mu     sync.RWMutex // guards closed against enqueue after Shutdown
+	closed bool
+	queue  chan *Span
+	done   chan struct{}
+}
+
+// NewTracer starts an exporter sending to endpoint (the OTLP/HTTP base URL,
+// e.g. http://collector:4318) on behalf of serviceName.
+func NewTracer(endpoint, serviceName string, logger *slog.Logger) *Tracer {
+	t := &Tracer{
+		url:         strings.TrimRight(endpoint, "/") + "/v1/traces",
+		serviceName: serviceName,
+		httpClient:  &http.Client{Timeout: exportTimeout},
+		logger:      logger,
+		queue:       make(chan *Span, queueSize),
+		done:        make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Shutdown stops accepting spans and flushes what is queued, giving up
+// when ctx is done. Safe on a nil *Tracer.
+func (t *Tracer) Shutdown(ctx context.Context) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.closed = true
+	close(t.queue)
+	t.mu.Unlock()
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		t.logger.Warn("span export did not finish before shutdown", logging.KeyError, ctx.Err())
+	}
+}
+
+// enqueue hands a finished span to the exporter, dropping it if the queue
+// is full or the tracer is shut down.
+func (t *Tracer) enqueue(s *Span) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return
+	}
+	select {
+	case t.queue <- s:
+	default:
+		t.logger.Debug("span queue full, dropping span", "span", s.name)
+	}
+}
+
+// run batches spans until the queue is closed.
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, maxBatch)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.export(batch); err != nil {
+			t.logger.Warn("span export failed", "spans", len(batch), logging.KeyError, err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case s, ok := <-t.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, s)
+			if len(batch) >= maxBatch {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// export POSTs one batch as an OTLP ExportTraceServiceRequest.
+func (t *Tracer) export(batch []*Span) error {
+	spans := make([]otlpSpan, len(batch))
+	for i, s := range batch {
+		spans[i] = s.otlp()
+	}
+	body, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{keyValue("service.name", t.serviceName)}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "coreos-worker"},
+			Spans: spans,
+		}},
+	}}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal spans: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// OTLP JSON encoding (ids as hex, 64-bit ints as strings)
+// ═══════════════════════════════════════════════════════════════════════════
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+// keyValue encodes an attribute; unsupported types are stringified.
+func keyValue(key string, value any) otlpKeyValue {
+	var v otlpAnyValue
+	switch x := value.(type) {
+	case string:
+		v.StringValue = &x
+	case bool:
+		v.BoolValue = &x
+	case int:
+		s := strconv.Itoa(x)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(x, 10)
+		v.IntValue = &s
+	default:
+		s := fmt.Sprint(x)
+		v.StringValue = &s
+	}
+	return otlpKeyValue{Key: key, Value: v}
+}
+
+// otlp converts a finished span. Attributes are sorted for stable output.
+func (s *Span) otlp() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.attributes))
+	for k := range s.attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]otlpKeyValue, 0, len(keys)+1)
+	for _, k := range keys {
+		attrs = append(attrs, keyValue(k, s.attributes[k]))
+	}
+
+	out := otlpSpan{
+		TraceID:           s.traceID,
+		SpanID:            s.spanID,
+		ParentSpanID:      s.parentID,
+		Name:              s.name,
+		Kind:              spanKindConsumer,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Status:            otlpStatus{Code: statusCodeOK},
+	}
+	if s.failed {
+		attrs = append(attrs, keyValue("coreos.error_code", s.errCode))
+		out.Status = otlpStatus{Code: statusCodeError, Message: s.errMsg}
+		out.Events = []otlpEvent{{
+			TimeUnixNano: out.EndTimeUnixNano,
+			Name:         "exception",
+			Attributes: []otlpKeyValue{
+				keyValue("exception.type", s.errCode),
+				keyValue("exception.message", s.errMsg),
+			},
+		}}
+	}
+	out.Attributes = attrs
+	return out
+}
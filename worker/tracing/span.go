@@ -0,0 +1,162 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Tracing Spans (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Minimal OpenTelemetry-compatible spans for job processing (OTEL_ENABLED).
+// Stdlib only — no SDK: spans are exported as OTLP/HTTP JSON. A nil
+// *Tracer and the nil *Span it returns are valid no-ops, so call sites
+// need no enabled/disabled branches.
+
+package tracing
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanContext identifies the parent of a span (W3C trace context).
+type SpanContext struct {
+	TraceID string // 32 lowercase hex
+	SpanID  string // 16 lowercase hex; "" when only the trace is known
+}
+
+// ParentFor returns the parent context for a job: the W3C traceparent TS
+// attached to the envelope if valid, otherwise the trace derived from the
+// Core OS traceId (the same mapping the API client uses for its
+// traceparent header), so worker spans join the TS trace either way.
+func ParentFor(traceParent, traceID string) SpanContext {
+	if sc, ok := ParseTraceParent(traceParent); ok {
+		return sc
+	}
+	return SpanContext{TraceID: W3CTraceID(traceID)}
+}
+
+// ParseTraceParent parses a version-00 W3C traceparent header.
+func ParseTraceParent(h string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(h), "-")
+	if len(parts) != 4 || parts[0] != "00" || !isHexID(parts[1], 32) || !isHexID(parts[2], 16) {
+		return SpanContext{}, false
+	}
+	return SpanContext{TraceID: parts[1], SpanID: parts[2]}, true
+}
+
+// W3CTraceID maps a Core OS traceId to a W3C trace-id. A 32-hex traceId is
+// used as-is; anything else is hashed to 16 bytes so the same traceId
+// always maps to the same trace-id.
+func W3CTraceID(traceID string) string {
+	if isHexID(traceID, 32) {
+		return traceID
+	}
+	sum := sha256.Sum256([]byte(traceID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// NewSpanID returns a random 8-byte span id as hex.
+func NewSpanID() string {
+	var id [8]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// isHexID reports whether s is n lowercase hex chars, not all zero.
+func isHexID(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	nonZero := false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'f':
+			if r != '0' {
+				nonZero = true
+			}
+		default:
+			return false
+		}
+	}
+	return nonZero
+}
+
+// Span is one timed operation. Methods are safe on a nil *Span.
+type Span struct {
+	tracer *Tracer
+
+	mu         sync.Mutex
+	name       string
+	traceID    string
+	spanID     string
+	parentID   string
+	start      time.Time
+	end        time.Time
+	attributes map[string]any
+	errCode    string
+	errMsg     string
+	failed     bool
+	ended      bool
+}
+
+// Start begins a span named name under parent. Returns nil if t is nil.
+func (t *Tracer) Start(name string, parent SpanContext) *Span {
+	if t == nil {
+		return nil
+	}
+	traceID := parent.TraceID
+	if traceID == "" {
+		traceID = W3CTraceID(NewSpanID() + NewSpanID())
+	}
+	return &Span{
+		tracer:     t,
+		name:       name,
+		traceID:    traceID,
+		spanID:     NewSpanID(),
+		parentID:   parent.SpanID,
+		start:      time.Now(),
+		attributes: make(map[string]any),
+	}
+}
+
+// SetAttribute records a string, bool or integer attribute.
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes[key] = value
+}
+
+// RecordError marks the span failed with a Core OS error code and message.
+// The first recorded error wins.
+func (s *Span) RecordError(code, msg string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failed {
+		return
+	}
+	s.failed = true
+	s.errCode = code
+	s.errMsg = msg
+}
+
+// End finishes the span and queues it for export. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.enqueue(s)
+}
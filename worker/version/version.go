@@ -0,0 +1,47 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Worker Build Version (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// The worker build version, injected at build time:
+//
+//	go build -ldflags "-X github.com/gemimi2525-star/super-platform/worker/version.Version=1.4.0" .
+//
+// Without it, the module version or VCS revision from the Go build info is
+// used, falling back to "dev". Reported in the User-Agent of every TS
+// request and on /healthz, so the fleet's builds can be audited from TS.
+
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is set via -ldflags -X; empty or "dev" means not injected.
+var Version = "dev"
+
+// String returns the build version.
+func String() string {
+	if Version != "" && Version != "dev" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && len(s.Value) >= 12 {
+			return "dev-" + s.Value[:12]
+		}
+	}
+	return "dev"
+}
+
+// UserAgent returns the User-Agent sent to TS, e.g.
+// "coreos-worker/1.4.0 (go1.22.5)".
+func UserAgent() string {
+	return "coreos-worker/" + String() + " (" + runtime.Version() + ")"
+}
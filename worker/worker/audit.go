@@ -0,0 +1,94 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Result Audit Sink (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Every result TS accepts is also handed to a ResultSink. The default sink
+// discards them; with AUDIT_ENABLED=true results are appended as JSON lines
+// under AUDIT_DIR, independent of TS. Records keep the HMAC signature and
+// traceId so the trail can be verified against the shared secret later.
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// auditFileName is the audit log inside AUDIT_DIR.
+const auditFileName = "results.jsonl"
+
+// ResultSink receives each result after TS has accepted it.
+// Implementations must be safe for concurrent use.
+type ResultSink interface {
+	RecordResult(result *contracts.JobResult) error
+	Close() error
+}
+
+// noopSink is the default ResultSink.
+type noopSink struct{}
+
+func (noopSink) RecordResult(*contracts.JobResult) error { return nil }
+func (noopSink) Close() error                            { return nil }
+
+// auditRecord is one line in the audit log: the signed result as posted,
+// plus when the worker recorded it.
+type auditRecord struct {
+	RecordedAt int64 `json:"recordedAt"`
+	*contracts.JobResult
+}
+
+// FileResultSink appends results to an append-only JSON-lines file.
+type FileResultSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileResultSink opens (creating if needed) the audit log in dir.
+func NewFileResultSink(dir string) (*FileResultSink, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create AUDIT_DIR: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, auditFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return &FileResultSink{file: file}, nil
+}
+
+// RecordResult appends one result as a JSON line.
+func (s *FileResultSink) RecordResult(result *contracts.JobResult) error {
+	line, err := json.Marshal(auditRecord{RecordedAt: time.Now().UnixMilli(), JobResult: result})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the audit log.
+func (s *FileResultSink) Close() error {
+	return s.file.Close()
+}
+
+// recordResult hands an accepted result to the sink. Audit failures are
+// logged, never surfaced: the result is already committed in TS.
+func (w *Worker) recordResult(result *contracts.JobResult) {
+	if err := w.sink.RecordResult(result); err != nil {
+		w.logger.Error("audit record failed",
+			logging.KeyJobID, result.JobID,
+			logging.KeyTraceID, result.TraceID,
+			logging.KeyError, err)
+	}
+}
@@ -0,0 +1,72 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Concurrency Auto-Tuning (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// AUTO_CONCURRENCY=true sizes the pool as NumCPU × multiplier (done in
+// config). With AUTO_CONCURRENCY_LATENCY_SLO_MS set, the number of jobs
+// claimed at once also follows AIMD against that SLO: a job slower than
+// the SLO halves the limit (at most once per backoff cooldown), and each
+// full round of on-SLO jobs raises it by one, up to the pool size.
+
+package worker
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// concurrencyCooldown spaces out decreases so one slow batch (whose jobs
+// all finish together) halves the limit once, not once per job.
+const concurrencyCooldown = 5 * time.Second
+
+// concurrencyTuner tracks the effective concurrency limit.
+type concurrencyTuner struct {
+	max    int
+	slo    time.Duration // 0 = fixed at max
+	logger *slog.Logger
+
+	mu           sync.Mutex
+	limit        int
+	good         int // on-SLO completions since the last change
+	lastDecrease time.Time
+}
+
+func newConcurrencyTuner(max int, slo time.Duration, logger *slog.Logger) *concurrencyTuner {
+	return &concurrencyTuner{max: max, slo: slo, logger: logger, limit: max}
+}
+
+// current returns the effective limit.
+func (t *concurrencyTuner) current() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
+
+// observe feeds one job's handler latency and returns the new limit.
+func (t *concurrencyTuner) observe(latency time.Duration) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.slo <= 0 {
+		return t.limit
+	}
+
+	if latency > t.slo {
+		t.good = 0
+		if t.limit > 1 && time.Since(t.lastDecrease) >= concurrencyCooldown {
+			t.limit = max(1, t.limit/2)
+			t.lastDecrease = time.Now()
+			t.logger.Warn("job latency above SLO, lowering concurrency",
+				"latencyMs", latency.Milliseconds(), "sloMs", t.slo.Milliseconds(), "concurrency", t.limit)
+		}
+		return t.limit
+	}
+
+	t.good++
+	if t.good >= t.limit && t.limit < t.max {
+		t.limit++
+		t.good = 0
+		t.logger.Debug("job latency within SLO, raising concurrency", "concurrency", t.limit)
+	}
+	return t.limit
+}
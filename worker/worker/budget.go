@@ -0,0 +1,69 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — In-Flight Memory Budget (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// MAX_INFLIGHT_BYTES caps the approximate memory held by running jobs: the
+// payload size is counted from claim and the encoded result size from
+// when it is built, both released when the job finishes. At or over the
+// budget no claim is sent; a claimed job that would push past it is
+// released back to TS, unless nothing is running (so one job larger than
+// the whole budget still runs instead of cycling forever).
+
+package worker
+
+import (
+	"context"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// overBudget reports whether in-flight bytes have reached MAX_INFLIGHT_BYTES.
+func (w *Worker) overBudget() bool {
+	limit := w.config.MaxInflightBytes
+	return limit > 0 && w.inflightBytes.Load() >= limit
+}
+
+// fitsBudget reports whether envelope can start without exceeding the budget.
+func (w *Worker) fitsBudget(envelope *client.JobEnvelope) bool {
+	limit := w.config.MaxInflightBytes
+	if limit <= 0 {
+		return true
+	}
+	current := w.inflightBytes.Load()
+	return current == 0 || current+int64(len(envelope.Payload)) <= limit
+}
+
+// releaseOverBudget hands a claimed job back to TS because running it would
+// exceed the in-flight budget. A dry-run peek holds no lease and is skipped.
+func (w *Worker) releaseOverBudget(ctx context.Context, envelope *client.JobEnvelope) {
+	jobID := envelope.Ticket.JobID
+	if !w.config.DryRun {
+		if err := w.apiClient.ReleaseJob(ctx, jobID, w.config.WorkerID, envelope.Attempts); err != nil {
+			w.logger.Error("lease release failed", logging.KeyJobID, jobID, logging.KeyError, err)
+			return
+		}
+	}
+	w.logger.Info("in-flight memory budget reached, released job",
+		logging.KeyJobID, jobID,
+		"payloadBytes", len(envelope.Payload),
+		"inflightBytes", w.inflightBytes.Load(),
+		"maxInflightBytes", w.config.MaxInflightBytes)
+}
+
+// trackResultBytes adds a built result's size to its running job's share.
+func (w *Worker) trackResultBytes(jobID string, n int) {
+	w.mu.Lock()
+	job, ok := w.inflight[jobID]
+	if ok {
+		job.bytes += int64(n)
+	}
+	w.mu.Unlock()
+	if ok {
+		w.addInflightBytes(int64(n))
+	}
+}
+
+func (w *Worker) addInflightBytes(n int64) {
+	w.metrics.SetGauge(metricInflightBytes, float64(w.inflightBytes.Add(n)), nil)
+}
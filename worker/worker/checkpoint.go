@@ -0,0 +1,74 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Job Progress Checkpoints (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Checkpoints emitted by a handler are held per job and posted to TS after
+// the next successful heartbeat, and once more if the attempt fails so a
+// retry can resume from the latest one. Posting is best-effort: failures
+// are logged and counted, never surfaced to the job.
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/jobs"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+)
+
+// jobProgress holds a job's latest checkpoint not yet posted to TS.
+type jobProgress struct {
+	mu      sync.Mutex
+	pending json.RawMessage
+}
+
+// checkpoint returns the jobs.Checkpoint handed to the job's handler.
+// Checkpoints that don't encode are dropped.
+func (p *jobProgress) checkpoint(logger *slog.Logger) jobs.Checkpoint {
+	return func(data any) {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			logger.Warn("checkpoint not JSON-encodable, dropped", logging.KeyError, err)
+			return
+		}
+		p.mu.Lock()
+		p.pending = encoded
+		p.mu.Unlock()
+	}
+}
+
+// take returns and clears the pending checkpoint (nil if none).
+func (p *jobProgress) take() json.RawMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending := p.pending
+	p.pending = nil
+	return pending
+}
+
+// postCheckpoint posts the job's pending checkpoint, if any. Once TS
+// reports it has no progress endpoint, checkpoints are discarded.
+func (w *Worker) postCheckpoint(ctx context.Context, jobID, traceID string, progress *jobProgress, logger *slog.Logger) {
+	pending := progress.take()
+	if pending == nil || w.progressUnsupported.Load() {
+		return
+	}
+	err := w.apiClient.PostProgress(ctx, jobID, w.config.WorkerID, traceID, pending)
+	switch {
+	case errors.Is(err, client.ErrProgressUnsupported):
+		w.logger.Info("progress checkpoints not supported by TS, discarding checkpoints")
+		w.progressUnsupported.Store(true)
+	case err != nil:
+		w.metrics.IncrCounter(metricCheckpoints, metrics.Labels{"result": "error"})
+		logger.Warn("checkpoint post failed", logging.KeyError, err)
+	default:
+		w.metrics.IncrCounter(metricCheckpoints, metrics.Labels{"result": "ok"})
+		logger.Debug("checkpoint sent")
+	}
+}
@@ -0,0 +1,215 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/clock"
+	"github.com/gemimi2525-star/super-platform/worker/config"
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+	"github.com/gemimi2525-star/super-platform/worker/workertest"
+)
+
+// newFakeClockWorker returns a worker on fake, against a mock TS whose
+// envelopes are dated on fake too.
+func newFakeClockWorker(t *testing.T, env map[string]string) (*Worker, *workertest.MockCoreOS, *clock.Fake) {
+	t.Helper()
+	ts := workertest.NewMockCoreOS()
+	t.Cleanup(ts.Close)
+	for k, v := range ts.Env() {
+		t.Setenv(k, v)
+	}
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() = %v", err)
+	}
+	w, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	fake := clock.NewFake(time.Now())
+	w.SetClock(fake)
+	ts.Clock = fake
+	return w, ts, fake
+}
+
+func TestProcessJobTicketExpiryFakeClock(t *testing.T) {
+	w, ts, fake := newFakeClockWorker(t, nil)
+
+	fresh := ts.Envelope("scheduler.tick", `{}`)
+	if outcome, err := w.ProcessJob(context.Background(), &fresh); err != nil || outcome.Status != "SUCCEEDED" {
+		t.Fatalf("fresh ticket: ProcessJob() = %+v, %v, want SUCCEEDED", outcome, err)
+	}
+
+	stale := ts.Envelope("scheduler.tick", `{}`)
+	fake.Advance(workertest.TicketTTL + time.Second)
+	outcome, err := w.ProcessJob(context.Background(), &stale)
+	if err != nil || outcome.ErrorCode != "TICKET_EXPIRED" {
+		t.Fatalf("expired ticket: ProcessJob() = %+v, %v, want TICKET_EXPIRED", outcome, err)
+	}
+}
+
+func TestProcessJobMaxAgeFakeClock(t *testing.T) {
+	w, ts, fake := newFakeClockWorker(t, map[string]string{"MAX_JOB_AGE_SECONDS": "60"})
+
+	envelope := ts.Envelope("scheduler.tick", `{}`)
+	fake.Advance(2 * time.Minute) // still within TicketTTL
+	outcome, err := w.ProcessJob(context.Background(), &envelope)
+	if err != nil || outcome.ErrorCode != "JOB_TOO_OLD" {
+		t.Fatalf("ProcessJob() = %+v, %v, want JOB_TOO_OLD", outcome, err)
+	}
+}
+
+func TestRecycleDueFakeClock(t *testing.T) {
+	w, _, fake := newFakeClockWorker(t, map[string]string{"MAX_LIFETIME_SECONDS": "3600"})
+
+	startedAt := fake.Now()
+	fake.Advance(59 * time.Minute)
+	if w.recycleDue(startedAt) {
+		t.Fatal("recycleDue() = true before MAX_LIFETIME_SECONDS")
+	}
+	fake.Advance(time.Minute)
+	if !w.recycleDue(startedAt) {
+		t.Fatal("recycleDue() = false at MAX_LIFETIME_SECONDS")
+	}
+}
+
+func TestDeliverResultRetriesOnFakeClock(t *testing.T) {
+	w, _, fake := newFakeClockWorker(t, map[string]string{"RESULT_POST_MAX_WAIT_SECONDS": "2"})
+
+	// The 2s budget covers the 500ms and 1s backoffs but not the 2s one
+	errUnavailable := errors.New("connection refused")
+	var posts atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.deliverResult(context.Background(), &contracts.JobResult{JobID: "job-1"}, func(context.Context) error {
+			posts.Add(1)
+			return errUnavailable
+		})
+		done <- err
+	}()
+
+	for i, backoff := range []time.Duration{500 * time.Millisecond, time.Second} {
+		waitForRetry(t, fake, &posts, i+1)
+		fake.Advance(backoff)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, errUnavailable) {
+			t.Fatalf("deliverResult() = %v, want the post error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deliverResult did not give up on the fake clock")
+	}
+	if got := posts.Load(); got != 3 {
+		t.Fatalf("posted %d times, want 3 (initial + 2 retries)", got)
+	}
+}
+
+// waitForRetry waits until post number n has failed and deliverResult is
+// waiting on fake for its backoff.
+func waitForRetry(t *testing.T, fake *clock.Fake, posts *atomic.Int32, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for posts.Load() != int32(n) || fake.Tickers() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a ticker on the fake clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestProcessJobReplayWithinClockSkew(t *testing.T) {
+	t.Cleanup(func() { contracts.ClockSkewMs = 0 })
+	w, ts, fake := newFakeClockWorker(t, map[string]string{"CLOCK_SKEW_MS": "10000"})
+
+	envelope := ts.Envelope("scheduler.tick", `{}`)
+	if outcome, err := w.ProcessJob(context.Background(), &envelope); err != nil || outcome.Status != "SUCCEEDED" {
+		t.Fatalf("first use: ProcessJob() = %+v, %v, want SUCCEEDED", outcome, err)
+	}
+
+	// Past ExpiresAt but inside the skew ValidateExpiry tolerates
+	// (the mock then refuses the second result for the attempt, so only
+	// the outcome is checked)
+	replay := envelope
+	fake.Advance(workertest.TicketTTL + 5*time.Second)
+	if outcome, _ := w.ProcessJob(context.Background(), &replay); outcome.ErrorCode != "TICKET_REPLAY" {
+		t.Fatalf("replay within skew: ProcessJob() = %+v, want TICKET_REPLAY", outcome)
+	}
+}
+
+func TestDrainFakeClock(t *testing.T) {
+	w, _, fake := newFakeClockWorker(t, nil)
+	w.mu.Lock()
+	w.inflight["job-1"] = &inflightJob{cancel: func() {}}
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.drain(fake.Now().Add(10 * time.Second))
+		close(done)
+	}()
+	waitForTicker(t, fake)
+	fake.Advance(9 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("drain returned before its deadline with a job active")
+	case <-time.After(50 * time.Millisecond):
+	}
+	fake.Advance(time.Second)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("drain did not return at its deadline on the fake clock")
+	}
+}
+
+func TestWALClaimedAtFakeClock(t *testing.T) {
+	w, ts, fake := newFakeClockWorker(t, map[string]string{"CRASH_RECOVERY": "true", "STATE_DIR": t.TempDir()})
+	fake.Advance(time.Hour) // well apart from the real clock
+	started, release := make(chan struct{}), make(chan struct{})
+	if err := w.RegisterHandler("test.block", func(context.Context, string, string) (any, error) {
+		close(started)
+		<-release
+		return map[string]any{}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	envelope := ts.Envelope("test.block", `{}`)
+	run := w.admitJob(context.Background(), &envelope)
+	finished := make(chan struct{})
+	go func() {
+		run()
+		close(finished)
+	}()
+	<-started
+	w.wal.mu.Lock()
+	entry := w.wal.pending[envelope.Ticket.JobID]
+	w.wal.mu.Unlock()
+	close(release)
+	<-finished
+	if want := fake.Now().UnixMilli(); entry.ClaimedAt != want {
+		t.Fatalf("WAL claimedAt = %d, want the worker clock's %d", entry.ClaimedAt, want)
+	}
+}
+
+// waitForTicker waits until something is waiting on fake.
+func waitForTicker(t *testing.T, fake *clock.Fake) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for fake.Tickers() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a ticker on the fake clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,100 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Dead-Letter Notification (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// When a job fails on its terminal attempt, optionally POST a notification
+// to DEAD_LETTER_WEBHOOK_URL so poison messages can page without waiting
+// for TS. Delivery is best-effort and never blocks the result post.
+// Failures caused by the worker stopping (WORKER_SHUTDOWN, INTERRUPTED) say
+// nothing about the job, so they never notify.
+
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// deadLetterEvent is the webhook body.
+type deadLetterEvent struct {
+	JobID        string `json:"jobId"`
+	JobType      string `json:"jobType"`
+	TraceID      string `json:"traceId"`
+	WorkerID     string `json:"workerId"`
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+	Attempts     int    `json:"attempts"`
+	MaxAttempts  int    `json:"maxAttempts"`
+}
+
+// workerStopCodes are error codes for attempts cut short by the worker
+// shutting down or restarting, not by the job itself.
+var workerStopCodes = map[string]bool{
+	"WORKER_SHUTDOWN": true,
+	"INTERRUPTED":     true,
+}
+
+// deadLetterNotifier posts dead-letter events to a webhook.
+type deadLetterNotifier struct {
+	url        string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+func newDeadLetterNotifier(url string, timeout time.Duration, logger *slog.Logger) *deadLetterNotifier {
+	return &deadLetterNotifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+	}
+}
+
+// notify sends the event in the background; failures are only logged.
+func (n *deadLetterNotifier) notify(event deadLetterEvent) {
+	go func() {
+		if err := n.send(event); err != nil {
+			n.logger.Error("dead-letter notification failed",
+				logging.KeyJobID, event.JobID,
+				logging.KeyTraceID, event.TraceID,
+				logging.KeyError, err)
+			return
+		}
+		n.logger.Info("dead-letter notification sent",
+			logging.KeyJobID, event.JobID,
+			logging.KeyTraceID, event.TraceID)
+	}()
+}
+
+func (n *deadLetterNotifier) send(event deadLetterEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead-letter event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.httpClient.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — In-Flight Jobs Debug Endpoint (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// /debug/jobs (DEBUG_ENABLED=true) lists the jobs this worker is executing
+// right now, oldest first, so a stuck job can be spotted without reading
+// logs. Payloads and results are never included. /debug/handlers lists the
+// jobTypes it can handle.
+
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// debugJob is one in-flight job on /debug/jobs.
+type debugJob struct {
+	JobID     string `json:"jobId"`
+	JobType   string `json:"jobType"`
+	TraceID   string `json:"traceId"`
+	Attempt   int    `json:"attempt"`
+	Affinity  string `json:"affinityKey,omitempty"`
+	StartedAt int64  `json:"startedAt"` // unix ms
+	ElapsedMs int64  `json:"elapsedMs"`
+}
+
+// debugHandler is one registered jobType on /debug/handlers.
+type debugHandler struct {
+	JobType string `json:"jobType"`
+	Version string `json:"version,omitempty"`
+	Allowed bool   `json:"allowed"` // passes JOB_TYPE_ALLOW/JOB_TYPE_DENY
+}
+
+// DebugHandlersHandler returns the /debug/handlers handler.
+func (w *Worker) DebugHandlersHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		routing := w.routing.Load()
+		types := w.dispatcher.RegisteredTypes()
+		handlers := make([]debugHandler, 0, len(types))
+		for _, jobType := range types {
+			handlers = append(handlers, debugHandler{
+				JobType: jobType,
+				Version: w.dispatcher.Version(jobType),
+				Allowed: routing.JobTypeAllowed(jobType),
+			})
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(rw).Encode(map[string]any{
+			"workerId": *w.workerID.Load(),
+			"handlers": handlers,
+		})
+	})
+}
+
+// DebugJobsHandler returns the /debug/jobs handler.
+func (w *Worker) DebugJobsHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		now := w.clock.Now()
+		w.mu.Lock()
+		jobs := make([]debugJob, 0, len(w.inflight))
+		for jobID, job := range w.inflight {
+			jobs = append(jobs, debugJob{
+				JobID:     jobID,
+				JobType:   job.envelope.Ticket.JobType,
+				TraceID:   job.envelope.Ticket.TraceID,
+				Attempt:   job.envelope.Attempts,
+				Affinity:  job.envelope.AffinityKey,
+				StartedAt: job.startedAt.UnixMilli(),
+				ElapsedMs: now.Sub(job.startedAt).Milliseconds(),
+			})
+		}
+		w.mu.Unlock()
+		sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt < jobs[j].StartedAt })
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(rw).Encode(map[string]any{
+			"workerId": *w.workerID.Load(),
+			"now":      now.UnixMilli(),
+			"active":   len(jobs),
+			"jobs":     jobs,
+		})
+	})
+}
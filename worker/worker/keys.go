@@ -0,0 +1,155 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Ticket Key Cache (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// With TICKET_KEYS_FETCH=true, tickets that don't verify against the static
+// JOB_TICKET_PUBLIC_KEY are tried against the keys TS lists at /api/keys.
+// The list is fetched at startup and cached for TICKET_KEYS_TTL_SECONDS; a
+// verification failure forces one refresh (at most every
+// minKeyRefreshInterval, so forged tickets can't hammer TS) before the
+// ticket is rejected. Fetched keys are only used if their SHA-256 is one of
+// TICKET_KEY_FINGERPRINTS, so a compromised or spoofed endpoint cannot
+// inject a signing key of its own. The fetch runs outside the cache lock, so
+// a slow /api/keys never blocks verification against the cached keys.
+
+package worker
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+)
+
+// minKeyRefreshInterval bounds how often a verification failure refetches.
+const minKeyRefreshInterval = 10 * time.Second
+
+// ticketKeyCache holds the pinned keys last fetched from TS.
+type ticketKeyCache struct {
+	fetch   func(ctx context.Context) ([]string, error)
+	pinned  []string // lowercase hex SHA-256 fingerprints
+	ttl     time.Duration
+	logger  *slog.Logger
+	metrics metrics.Metrics
+
+	mu          sync.Mutex
+	keys        [][]byte
+	fetchedAt   time.Time // last successful fetch
+	attemptedAt time.Time // last fetch, successful or not
+}
+
+func newTicketKeyCache(fetch func(ctx context.Context) ([]string, error), pinned []string, ttl time.Duration, logger *slog.Logger, m metrics.Metrics) *ticketKeyCache {
+	return &ticketKeyCache{fetch: fetch, pinned: pinned, ttl: ttl, logger: logger, metrics: m}
+}
+
+// current returns the cached keys, refetching them once the TTL is up.
+func (k *ticketKeyCache) current(ctx context.Context) [][]byte {
+	k.mu.Lock()
+	due := time.Since(k.fetchedAt) >= k.ttl && time.Since(k.attemptedAt) >= minKeyRefreshInterval
+	k.mu.Unlock()
+	if due {
+		k.refresh(ctx)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.keys
+}
+
+// refresh refetches the keys unless that was tried very recently, and
+// reports whether a fetch was sent. On failure the previous keys are kept.
+func (k *ticketKeyCache) refresh(ctx context.Context) bool {
+	k.mu.Lock()
+	if time.Since(k.attemptedAt) < minKeyRefreshInterval {
+		k.mu.Unlock()
+		return false
+	}
+	attempt := time.Now()
+	k.attemptedAt = attempt
+	cached := len(k.keys)
+	k.mu.Unlock()
+
+	advertised, err := k.fetch(ctx)
+	if err != nil {
+		k.metrics.IncrCounter(metricTicketKeyRefresh, metrics.Labels{"result": "error"})
+		k.logger.Warn("ticket key fetch failed, keeping cached keys", logging.KeyError, err, "cachedKeys", cached)
+		return true
+	}
+	keys := k.pin(advertised)
+
+	k.mu.Lock()
+	// A fetch that outlived minKeyRefreshInterval must not overwrite the
+	// result of a later one
+	if attempt.After(k.fetchedAt) {
+		k.keys = keys
+		k.fetchedAt = attempt
+	}
+	k.mu.Unlock()
+	k.metrics.IncrCounter(metricTicketKeyRefresh, metrics.Labels{"result": "ok"})
+	k.logger.Info("ticket keys refreshed", "advertised", len(advertised), "accepted", len(keys))
+	return true
+}
+
+// pin decodes the advertised keys, keeping those with a pinned fingerprint.
+func (k *ticketKeyCache) pin(advertised []string) [][]byte {
+	var keys [][]byte
+	for _, encoded := range advertised {
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			k.logger.Warn("ignoring malformed ticket key from TS", "key", encoded)
+			continue
+		}
+		if fp, ok := k.pinnedKey(raw); !ok {
+			k.logger.Error("ignoring ticket key from TS not in TICKET_KEY_FINGERPRINTS", "fingerprint", "SHA256:"+fp)
+			continue
+		}
+		keys = append(keys, raw)
+	}
+	return keys
+}
+
+// pinnedKey reports whether raw matches a pinned fingerprint, returning
+// its full hex fingerprint.
+func (k *ticketKeyCache) pinnedKey(raw []byte) (string, bool) {
+	sum := sha256.Sum256(raw)
+	fp := hex.EncodeToString(sum[:])
+	for _, pin := range k.pinned {
+		if fp == pin {
+			return fp, true
+		}
+	}
+	return fp, false
+}
+
+// verifyTicket checks the ticket signature against the static key, then
+// the cached TS keys, then freshly fetched ones; the static key's error is
+// returned if none match.
+func (w *Worker) verifyTicket(ctx context.Context, ticket *contracts.JobTicket) error {
+	err := ticket.VerifySignature(w.publicKey)
+	if err == nil || w.ticketKeys == nil {
+		return err
+	}
+	if verifiesWithAny(ticket, w.ticketKeys.current(ctx)) {
+		return nil
+	}
+	if w.ticketKeys.refresh(ctx) && verifiesWithAny(ticket, w.ticketKeys.current(ctx)) {
+		return nil
+	}
+	return err
+}
+
+func verifiesWithAny(ticket *contracts.JobTicket, keys [][]byte) bool {
+	for _, key := range keys {
+		if ticket.VerifySignature(key) == nil {
+			return true
+		}
+	}
+	return false
+}
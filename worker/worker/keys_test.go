@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+)
+
+// newTestKey returns a base64 Ed25519 public key and its fingerprint.
+func newTestKey(t *testing.T) (string, string) {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(pub)
+	return base64.StdEncoding.EncodeToString(pub), hex.EncodeToString(sum[:])
+}
+
+func TestTicketKeyCachePinsFullFingerprint(t *testing.T) {
+	pinnedKey, pinnedFP := newTestKey(t)
+	otherKey, otherFP := newTestKey(t)
+	fetch := func(context.Context) ([]string, error) { return []string{pinnedKey, otherKey}, nil }
+	// A prefix of the other key's fingerprint doesn't pin it
+	k := newTicketKeyCache(fetch, []string{pinnedFP, otherFP[:16]}, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)), metrics.Nop)
+
+	keys := k.current(context.Background())
+	if len(keys) != 1 || base64.StdEncoding.EncodeToString(keys[0]) != pinnedKey {
+		t.Fatalf("current() = %d keys, want only the pinned one", len(keys))
+	}
+}
+
+func TestTicketKeyCacheFetchesOutsideLock(t *testing.T) {
+	key, fp := newTestKey(t)
+	calls := 0
+	release := make(chan struct{})
+	fetching := make(chan struct{})
+	fetch := func(context.Context) ([]string, error) {
+		calls++
+		if calls == 2 {
+			close(fetching)
+			<-release
+		}
+		return []string{key}, nil
+	}
+	k := newTicketKeyCache(fetch, []string{fp}, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)), metrics.Nop)
+	if keys := k.current(context.Background()); len(keys) != 1 {
+		t.Fatalf("current() = %d keys, want 1", len(keys))
+	}
+
+	// Let a forced refresh hang in fetch; the cached keys stay readable
+	k.mu.Lock()
+	k.attemptedAt = time.Time{}
+	k.mu.Unlock()
+	done := make(chan bool)
+	go func() { done <- k.refresh(context.Background()) }()
+	<-fetching
+
+	read := make(chan int)
+	go func() { read <- len(k.current(context.Background())) }()
+	select {
+	case n := <-read:
+		if n != 1 {
+			t.Fatalf("current() during fetch = %d keys, want the cached 1", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("current() blocked on an in-flight fetch")
+	}
+	if k.refresh(context.Background()) {
+		t.Fatal("refresh() during an in-flight fetch sent another one")
+	}
+
+	close(release)
+	if !<-done {
+		t.Fatal("refresh() = false, want a fetch sent")
+	}
+}
@@ -0,0 +1,48 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Local Job Run (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Runs one envelope through the full ProcessJob pipeline (signature, scope,
+// expiry, hash, schema, dispatch, result signing) without talking to TS,
+// for handler debugging and reproductions (--run-file).
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+)
+
+// RunLocal processes envelope with the configured keys and returns the
+// signed result that would have been posted (nil in dry-run, or if
+// processing stopped before a result was built). Heartbeats, result posts,
+// dead-letter notifications and the audit sink are all skipped, and only
+// the static JOB_TICKET_PUBLIC_KEY is used (no /api/keys fetch). The
+// worker must not be running its poll loop.
+func (w *Worker) RunLocal(ctx context.Context, envelope *client.JobEnvelope) (*contracts.JobResult, ProcessOutcome, error) {
+	var captured *contracts.JobResult
+	w.resultCapture = func(result *contracts.JobResult) {
+		captured = result
+	}
+	ticketKeys := w.ticketKeys
+	w.ticketKeys = nil
+	defer func() {
+		w.resultCapture = nil
+		w.ticketKeys = ticketKeys
+	}()
+
+	outcome, err := w.ProcessJob(ctx, envelope)
+	return captured, outcome, err
+}
+
+// RunLocalAt is RunLocal with the ticket's expiry and age checked as of
+// claimedAt (e.g. when a recorded envelope was claimed), and the handler
+// given the time the ticket had left then.
+func (w *Worker) RunLocalAt(ctx context.Context, envelope *client.JobEnvelope, claimedAt time.Time) (*contracts.JobResult, ProcessOutcome, error) {
+	w.localClaimedAt = claimedAt
+	defer func() { w.localClaimedAt = time.Time{} }()
+	return w.RunLocal(ctx, envelope)
+}
@@ -3,24 +3,38 @@
 // ═══════════════════════════════════════════════════════════════════════════
 //
 // Main polling loop with lease/heartbeat, retry reporting, graceful shutdown,
-// and structured logging.
+// and structured logging. Claimed jobs run on a bounded worker pool
+// (MAX_CONCURRENCY slots), optionally claimed in batches and ahead of
+// execution into a local queue (see queue.go).
 
 package worker
 
 import (
+	"cmp"
 	"context"
+	"crypto/cipher"
 	"encoding/base64"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/clock"
 	"github.com/gemimi2525-star/super-platform/worker/config"
 	"github.com/gemimi2525-star/super-platform/worker/contracts"
 	"github.com/gemimi2525-star/super-platform/worker/jobs"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+	"github.com/gemimi2525-star/super-platform/worker/tracing"
 )
 
 // Worker is the main polling loop.
@@ -29,156 +43,1300 @@ type Worker struct {
 	dispatcher *jobs.Dispatcher
 	apiClient  *client.APIClient
 	publicKey  []byte
+	ticketKeys *ticketKeyCache // nil unless TICKET_KEYS_FETCH=true
+	payloadKey cipher.AEAD     // nil unless PAYLOAD_ENCRYPTION_KEY is set
+	nonces     *contracts.NonceCache
+	clock      clock.Clock // job timing and heartbeats; see SetClock
+	metrics    metrics.Metrics
+	prometheus *metrics.Prometheus // backs MetricsHandler; nil after SetMetrics
+	logger     *slog.Logger
+	deadLetter *deadLetterNotifier // nil unless DEAD_LETTER_WEBHOOK_URL is set
+	sink       ResultSink          // receives every result TS accepts
+	wal        *jobWAL             // nil unless CRASH_RECOVERY=true
+	tracer     *tracing.Tracer     // nil unless OTEL_ENABLED=true
+	recorder   *envelopeRecorder   // nil unless RECORD_ENVELOPES_DIR is set
+	spool      *resultSpool        // nil unless RESULT_SPOOL_DIR is set
+
+	// Set by RunLocal: signed results go here instead of TS, no heartbeats
+	resultCapture func(*contracts.JobResult)
+
+	// Set by RunLocalAt: ticket time checks run as of this claim time
+	localClaimedAt time.Time
+
+	// Worker pool: one slot per concurrently executing job; claims stop at
+	// the tuner's limit (the full pool unless AIMD has backed off)
+	slots chan struct{}
+	tuner *concurrencyTuner
+
+	// Claimed jobs waiting for an executor (nil unless LOCAL_QUEUE_DEPTH > 0;
+	// see queue.go)
+	queue chan *client.JobEnvelope
+
+	// Claim rate limit (nil = unlimited)
+	limiter *rate.Limiter
+
+	// Current WorkerID as logged (see renameWorker)
+	workerID *atomic.Pointer[string]
+
+	// No claims before this time (TS sent 429 with Retry-After, capped at
+	// CLAIM_RETRY_AFTER_MAX_SECONDS); loop-only
+	claimPausedUntil time.Time
+
+	// The pool was saturated since the last claim (CLAIM_BUSY_HINT); loop-only
+	wasSaturated bool
+
+	// jobType allow/deny and caps, swapped by a SIGHUP reload (see routing.go)
+	routing atomic.Pointer[config.Routing]
+
+	// Set by SIGUSR2: no new claims, and Run returns ErrRestart once idle
+	restarting atomic.Bool
+
+	// Stop claiming but keep running (toggled by SIGHUP or SetDraining)
+	draining atomic.Bool
+
+	// Set when a claim fails authentication with EXIT_ON_AUTH_FAILURE=true:
+	// no new claims, and Run returns client.ErrAuthFailed once shut down
+	authFailed atomic.Bool
+
+	// Self-recycling: jobs claimed so far, and set once a limit is hit
+	jobsClaimed atomic.Int64
+	recycling   atomic.Bool
+
+	// Long-poll claim falls back to ticker polling once TS reports 404/501
+	longPollUnsupported atomic.Bool
+
+	// Batch claim falls back to single claim once TS reports 404
+	batchUnsupported bool
+
+	// Chunked result upload falls back to single-shot once TS reports 404
+	streamUnsupported atomic.Bool
+
+	// Progress checkpoints are discarded once TS reports 404/501
+	progressUnsupported atomic.Bool
 
 	// Graceful shutdown
-	mu         sync.Mutex
-	processing bool // true if currently executing a job
+	mu       sync.Mutex
+	inflight map[string]*inflightJob // executing jobs by jobId
+	queued   map[string]*queuedJob   // jobs in the local queue by jobId
+
+	// Sum of inflightJob.bytes (see budget.go)
+	inflightBytes atomic.Int64
+
+	// Health probes
+	graceUntil   atomic.Int64 // unix nanos when STARTUP_GRACE_SECONDS ends; 0 before Run
+	lastTick     atomic.Int64 // unix nanos of the last poll loop iteration
+	pollInterval atomic.Int64 // current (adaptive) poll interval
+	ready        atomic.Bool  // true after the first successful claim round-trip
+	claimFails   atomic.Int64 // consecutive failed claims; reset by a successful one
 }
 
+// ErrRestart is returned by Run after a SIGUSR2-requested drain, once the
+// worker has shut down and the process can re-exec its binary.
+var ErrRestart = errors.New("restart requested")
+
+// envelopeOverheadBytes is the claim response allowance on top of
+// MAX_PAYLOAD_BYTES for the ticket and envelope fields.
+const envelopeOverheadBytes = 64 << 10
+
 // New creates a new Worker instance.
-func New(cfg *config.Config) (*Worker, error) {
+func New(cfg *config.Config, logger *slog.Logger) (*Worker, error) {
 	// Decode public key from base64
 	pubKey, err := base64.StdEncoding.DecodeString(cfg.PublicKeyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_TICKET_PUBLIC_KEY: %w", err)
+	}
+
+	var payloadKey cipher.AEAD
+	if cfg.PayloadEncryptionKey != "" {
+		rawKey, err := base64.StdEncoding.DecodeString(cfg.PayloadEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PAYLOAD_ENCRYPTION_KEY: %w", err)
+		}
+		if payloadKey, err = client.NewPayloadCipher(rawKey); err != nil {
+			return nil, fmt.Errorf("invalid PAYLOAD_ENCRYPTION_KEY: %w", err)
+		}
+	}
+
+	// Resolved per record: register may still suffix a generated ID
+	workerID := new(atomic.Pointer[string])
+	initialID := cfg.WorkerID
+	workerID.Store(&initialID)
+	logger = logging.Dynamic(logger, logging.KeyWorkerID, func() string { return *workerID.Load() })
+	contracts.ClockSkewMs = cfg.ClockSkew.Milliseconds()
+	contracts.ResultSignatureVersion = cfg.ResultSignatureVersion
+
+	clientOpts := []client.Option{
+		client.WithRetry(client.RetryPolicy{
+			MaxRetries: cfg.HTTPMaxRetries,
+			BaseDelay:  cfg.HTTPRetryBase,
+		}),
+		client.WithLogger(logging.Component(logger, "APIClient")),
+		client.WithW3CTrace(cfg.TraceW3C),
+		client.WithFailoverURLs(cfg.APIStandbyURLs...),
+		client.WithOperationURLs(cfg.ResultURL, cfg.HeartbeatURL),
+		client.WithJobTypeFilter(cfg.JobTypeAllow, cfg.JobTypeDeny),
+		client.WithMinPriority(cfg.ClaimMinPriority),
+		client.WithAffinity(cfg.ClaimAffinity),
+		client.WithVisibilityTimeout(int(cfg.VisibilityTimeout/time.Second), cfg.JobTypeVisibilityTimeout),
+		client.WithConnPool(cfg.MaxIdleConns, cfg.MaxConnsPerHost, cfg.IdleConnTimeout),
+		client.WithDialTimeouts(cfg.DialTimeout, cfg.TLSHandshakeTimeout),
+		client.WithMaxJobBytes(int64(cfg.MaxPayloadBytes) + envelopeOverheadBytes),
+		client.WithResultDedupCacheSize(cfg.ResultDedupCacheSize),
+	}
+
+	// Request authentication / identity (optional)
+	if cfg.AuthToken != "" {
+		clientOpts = append(clientOpts, client.WithAuthToken(cfg.AuthToken))
+	}
+	if cfg.WorkerIDHeader {
+		clientOpts = append(clientOpts, client.WithWorkerIDHeader(cfg.WorkerID))
+	}
+	if len(cfg.ExtraHTTPHeaders) > 0 {
+		clientOpts = append(clientOpts, client.WithExtraHeaders(cfg.ExtraHTTPHeaders))
+	}
+
+	// Signed result acks (optional, requires TS support)
+	if cfg.ExpectAck {
+		clientOpts = append(clientOpts, client.WithResultAck(cfg.HMACSecret))
+	}
+
+	// Gzip large result bodies (optional, requires TS support)
+	if cfg.CompressResults {
+		clientOpts = append(clientOpts, client.WithResultCompression(cfg.CompressResultsMinBytes))
+	}
+
+	// TLS version / cipher policy, plus mTLS when cert files are set
+	tlsCfg, err := client.LoadTLSConfig(cfg.ClientCertFile, cfg.ClientKeyFile, cfg.CACertFile)
 	if err != nil {
 		return nil, err
 	}
+	tlsCfg.MinVersion = cfg.TLSMinVersion
+	tlsCfg.CipherSuites = cfg.TLSCipherSuites
+	clientOpts = append(clientOpts, client.WithTLSConfig(tlsCfg))
+
+	// Stop claiming while TS keeps failing
+	clientOpts = append(clientOpts, client.WithCircuitBreaker(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown))
+
+	promMetrics := newPrometheusMetrics()
+	clientOpts = append(clientOpts, client.WithMetrics(promMetrics))
+
+	apiClient := client.NewAPIClient(cfg.APIURL, cfg.HTTPTimeout, clientOpts...)
 
-	return &Worker{
+	var deadLetter *deadLetterNotifier
+	if cfg.DeadLetterWebhookURL != "" {
+		deadLetter = newDeadLetterNotifier(cfg.DeadLetterWebhookURL, cfg.HTTPTimeout, logging.Component(logger, "DeadLetter"))
+	}
+
+	var sink ResultSink = noopSink{}
+	if cfg.AuditEnabled {
+		fileSink, err := NewFileResultSink(cfg.AuditDir)
+		if err != nil {
+			return nil, err
+		}
+		sink = fileSink
+	}
+
+	var recorder *envelopeRecorder
+	if cfg.RecordEnvelopesDir != "" {
+		if recorder, err = newEnvelopeRecorder(cfg.RecordEnvelopesDir, logging.Component(logger, "Recorder")); err != nil {
+			return nil, err
+		}
+	}
+
+	var spool *resultSpool
+	if cfg.ResultSpoolDir != "" {
+		if spool, err = newResultSpool(cfg.ResultSpoolDir, logging.Component(logger, "Spool")); err != nil {
+			return nil, err
+		}
+	}
+
+	var tracer *tracing.Tracer
+	if cfg.OTelEnabled {
+		tracer = tracing.NewTracer(cfg.OTelEndpoint, cfg.OTelServiceName, logging.Component(logger, "Tracing"))
+	}
+
+	dispatcher := jobs.NewDispatcher(logging.Component(logger, "Dispatcher"))
+	dispatcher.SetMetrics(promMetrics)
+	if cfg.AllowPrivateTargets {
+		dispatcher.AllowPrivateTargets()
+	}
+	if cfg.EnableTestHandlers {
+		dispatcher.IncludeTestTypes()
+		if err := dispatcher.Register("__test.echo", jobs.NewTestEchoHandler(cfg.WorkerID)); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &Worker{
 		config:     cfg,
-		dispatcher: jobs.NewDispatcher(),
-		apiClient:  client.NewAPIClient(cfg.APIURL, cfg.HTTPTimeout),
+		dispatcher: dispatcher,
+		apiClient:  apiClient,
 		publicKey:  pubKey,
-	}, nil
+		payloadKey: payloadKey,
+		nonces:     contracts.NewNonceCache(cfg.NonceCacheSize),
+		clock:      clock.Real,
+		metrics:    promMetrics,
+		prometheus: promMetrics,
+		slots:      make(chan struct{}, cfg.MaxConcurrency),
+		tuner:      newConcurrencyTuner(cfg.MaxConcurrency, cfg.ConcurrencyLatencySLO, logging.Component(logger, "Worker")),
+		inflight:   make(map[string]*inflightJob),
+		queued:     make(map[string]*queuedJob),
+		logger:     logging.Component(logger, "Worker"),
+		deadLetter: deadLetter,
+		sink:       sink,
+		tracer:     tracer,
+		recorder:   recorder,
+		spool:      spool,
+		limiter:    newClaimLimiter(cfg),
+		workerID:   workerID,
+	}
+
+	routing := cfg.Routing
+	w.routing.Store(&routing)
+
+	if cfg.LocalQueueDepth > 0 {
+		w.queue = make(chan *client.JobEnvelope, cfg.LocalQueueDepth)
+	}
+
+	// Signing keys from TS, for rotation without a redeploy (optional)
+	if cfg.TicketKeysFetch {
+		w.ticketKeys = newTicketKeyCache(apiClient.FetchPublicKeys, cfg.TicketKeyFingerprints, cfg.TicketKeysTTL,
+			logging.Component(logger, "TicketKeys"), promMetrics)
+	}
+
+	// Crash recovery (optional; dry-run never holds leases)
+	if cfg.CrashRecovery && !cfg.DryRun {
+		wal, interrupted, err := openJobWAL(cfg.StateDir)
+		if err != nil {
+			return nil, err
+		}
+		w.wal = wal
+		w.recoverInterrupted(interrupted)
+	}
+
+	return w, nil
 }
 
 // Run starts the polling loop. Blocks until SIGTERM/SIGINT or context cancel.
-func (w *Worker) Run(ctx context.Context) {
-	log.Printf("[Worker] Starting %s (poll every %s)", w.config.WorkerID, w.config.PollInterval)
+// SIGHUP toggles draining: claims stop while in-flight jobs finish, and a
+// second SIGHUP resumes claiming. With SIGHUP_ACTION=reload it reloads the
+// jobType routing from WORKER_CONFIG_FILE instead. Run also returns once MAX_JOBS_BEFORE_EXIT
+// or MAX_LIFETIME_SECONDS is reached and the last in-flight job is done.
+// SIGUSR2 stops claiming the same way, and once in-flight jobs are done Run
+// shuts down cleanly and returns ErrRestart so the caller can re-exec.
+// It fails fast, before claiming anything, if TS reports WORKER_ID as
+// already active (unless ALLOW_DUPLICATE_WORKER_ID=true). With
+// EXIT_ON_AUTH_FAILURE=true a claim rejected with 401/403 shuts the worker
+// down and Run returns an error matching client.ErrAuthFailed.
+func (w *Worker) Run(ctx context.Context) error {
+	w.logger.Info("starting worker",
+		"pollInterval", w.config.PollInterval.String(),
+		"maxPollInterval", w.config.MaxPollInterval.String(),
+		"concurrency", w.config.MaxConcurrency,
+		"autoConcurrency", w.config.AutoConcurrency,
+		"batchSize", w.config.ClaimBatchSize,
+		"localQueueDepth", w.config.LocalQueueDepth,
+		"claimMode", w.config.ClaimMode,
+		"dryRun", w.config.DryRun,
+		"handlers", w.dispatcher.RegisteredTypes())
 
 	// Set up signal handler for graceful shutdown
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	usr2 := make(chan os.Signal, 1)
+	signal.Notify(usr2, syscall.SIGUSR2)
+	defer signal.Stop(usr2)
+	restart := false
 
-	ticker := time.NewTicker(w.config.PollInterval)
-	defer ticker.Stop()
+	if err := w.register(ctx); err != nil {
+		if w.wal != nil {
+			w.wal.close()
+		}
+		w.sink.Close()
+		return err
+	}
+	if w.ticketKeys != nil {
+		w.ticketKeys.refresh(ctx)
+	}
+	w.metrics.SetGauge(metricConcurrency, float64(w.tuner.current()), nil)
+	if w.spool != nil && !w.config.DryRun {
+		go w.spoolLoop(ctx)
+	}
+	if w.queue != nil {
+		for i := range w.config.MaxConcurrency {
+			go w.executor(ctx, i)
+		}
+	}
+
+	startedAt := w.clock.Now()
+	interval := w.config.PollInterval
+
+	// Spread a fleet restarted together across the poll interval; later
+	// ticks keep the random phase since the timer resets from each tick
+	first := interval
+	if w.config.PollJitter {
+		first = rand.N(interval)
+		w.logger.Info("delaying first poll", "jitter", first.Round(time.Millisecond).String())
+	}
+	timer := time.NewTimer(first)
+	defer timer.Stop()
+
+	w.lastTick.Store(w.clock.Now().UnixNano())
+	w.pollInterval.Store(int64(interval))
+	w.graceUntil.Store(startedAt.Add(w.config.StartupGrace).UnixNano())
 
 	for {
 		select {
 		case <-ctx.Done():
-			w.mu.Lock()
-			isProcessing := w.processing
-			w.mu.Unlock()
-
-			if isProcessing {
-				log.Printf("[Worker] Received shutdown signal, waiting for current job to finish...")
+			w.releaseQueued()
+			if w.authFailed.Load() {
+				w.logger.Error("exiting on authentication failure", "active", w.activeJobs())
+			} else if restart {
+				w.logger.Info("restart requested and no active job — exiting for re-exec")
+			} else if w.recycling.Load() {
+				w.logger.Info("recycle limit reached and no active job — exiting for restart")
+			} else if active := w.activeJobs(); active > 0 {
+				w.logger.Info("received shutdown signal, waiting for active jobs to finish", "active", active)
 			} else {
-				log.Printf("[Worker] Received shutdown signal, no active job — exiting cleanly")
+				w.logger.Info("received shutdown signal, no active job — exiting cleanly")
 			}
-			// Wait briefly for current job to finish (if any)
-			for i := 0; i < 30; i++ {
-				w.mu.Lock()
-				done := !w.processing
-				w.mu.Unlock()
-				if done {
-					break
-				}
-				time.Sleep(1 * time.Second)
+			// Wait for current jobs to finish (if any), then report the rest
+			start := w.clock.Now()
+			w.drain(start.Add(w.config.ShutdownDrain))
+			w.abandonInflight(start.Add(w.config.ShutdownTimeout))
+			w.deregister()
+			if w.wal != nil {
+				w.wal.close()
 			}
-			log.Printf("[Worker] Shutdown complete")
-			return
-		case <-ticker.C:
-			w.processNextJob(ctx)
+			w.sink.Close()
+			w.flushSpans()
+			w.logger.Info("shutdown complete")
+			if w.authFailed.Load() {
+				return fmt.Errorf("claim rejected: %w", client.ErrAuthFailed)
+			}
+			if restart {
+				return ErrRestart
+			}
+			return nil
+		case <-usr2:
+			if !w.restarting.Swap(true) {
+				w.logger.Info("restart requested, no new claims; re-exec once in-flight jobs finish", "active", w.activeJobs())
+			}
+		case <-hup:
+			if w.config.SighupAction == "reload" {
+				w.reloadRouting()
+			} else {
+				w.SetDraining(!w.draining.Load())
+			}
+		case <-timer.C:
+			w.lastTick.Store(w.clock.Now().UnixNano())
+			if w.authFailed.Load() {
+				cancel()
+				continue
+			}
+			if w.restarting.Load() && w.activeJobs() == 0 {
+				restart = true
+				cancel()
+				continue
+			}
+			if w.recycleDue(startedAt) && w.activeJobs() == 0 {
+				cancel()
+				continue
+			}
+			result := w.processNextJob(ctx)
+			interval = w.nextPollInterval(interval, result)
+			w.pollInterval.Store(int64(interval))
+			timer.Reset(interval)
 		}
 	}
 }
 
-// processNextJob handles one iteration of the polling loop.
-func (w *Worker) processNextJob(ctx context.Context) {
-	envelope, err := w.apiClient.ClaimJob(w.config.WorkerID)
-	if err != nil {
-		log.Printf("[Worker] Claim error: %v", err)
+// recycleDue reports whether a self-recycling limit has been reached,
+// logging (once) when claiming stops because of it.
+func (w *Worker) recycleDue(startedAt time.Time) bool {
+	if w.recycling.Load() {
+		return true
+	}
+	jobsDone := w.config.MaxJobsBeforeExit > 0 && w.jobsClaimed.Load() >= int64(w.config.MaxJobsBeforeExit)
+	expired := w.config.MaxLifetime > 0 && clock.Since(w.clock, startedAt) >= w.config.MaxLifetime
+	if !jobsDone && !expired {
+		return false
+	}
+	w.recycling.Store(true)
+	w.logger.Info("recycle limit reached, no new claims; exiting once in-flight jobs finish",
+		"jobsClaimed", w.jobsClaimed.Load(),
+		"uptime", clock.Since(w.clock, startedAt).Round(time.Second).String(),
+		"active", w.activeJobs())
+	return true
+}
+
+// SetClock replaces the clock used for ticket expiry and age, the nonce
+// cache, heartbeat cadence, claim pauses, result post retries, recycling
+// and job latency (default clock.Real), e.g. with a clock.Fake in tests.
+// Must be called before Run.
+func (w *Worker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// SetDraining stops (true) or resumes (false) claiming new jobs. In-flight
+// jobs keep heartbeating and post their results either way.
+func (w *Worker) SetDraining(on bool) {
+	if w.draining.Swap(on) == on {
 		return
 	}
+	if on {
+		w.logger.Info("draining: no new claims until resumed", "active", w.activeJobs())
+	} else {
+		w.logger.Info("drain cancelled, resuming claims")
+	}
+}
+
+// Draining reports whether claiming is paused by SetDraining.
+func (w *Worker) Draining() bool {
+	return w.draining.Load()
+}
+
+// pollResult is what one processNextJob call observed.
+type pollResult int
+
+const (
+	pollSkipped pollResult = iota // no claim sent (no free slot, draining, paused, rate limited)
+	pollFailed                    // claim request failed
+	pollEmpty                     // TS had no work
+	pollClaimed                   // at least one job claimed
+)
+
+// nextPollInterval doubles the interval after an empty claim, up to
+// MAX_POLL_INTERVAL_SECONDS, and resets it as soon as the queue has work.
+// Long-poll claims are re-issued immediately (TS did the waiting), with
+// the same doubling as reconnect backoff after a failed request.
+func (w *Worker) nextPollInterval(current time.Duration, result pollResult) time.Duration {
+	if w.longPolling() {
+		switch result {
+		case pollEmpty, pollClaimed:
+			return 0
+		case pollFailed:
+			return min(max(current*2, w.config.PollInterval), w.config.MaxPollInterval)
+		}
+		return w.config.PollInterval
+	}
+	if result != pollEmpty {
+		return w.config.PollInterval
+	}
+	return min(current*2, w.config.MaxPollInterval)
+}
+
+// longPolling reports whether claims currently use the long-poll endpoint.
+func (w *Worker) longPolling() bool {
+	return w.config.ClaimMode == "longpoll" && !w.config.DryRun && !w.longPollUnsupported.Load()
+}
+
+// Alive reports whether the poll loop has ticked within 3× the current
+// poll interval (plus the long-poll wait when claims block on TS) and
+// fewer than CLAIM_FAILURE_THRESHOLD claims in a row have failed. The
+// failure threshold is not applied while Starting.
+func (w *Worker) Alive() bool {
+	last := w.lastTick.Load()
+	if last == 0 {
+		return false
+	}
+	limit := 3 * max(time.Duration(w.pollInterval.Load()), w.config.PollInterval)
+	if w.longPolling() {
+		limit += w.config.LongPollWait + w.config.HTTPTimeout // a claim may block this long
+	}
+	if w.claimFails.Load() >= int64(w.config.ClaimFailureThreshold) && !w.Starting() {
+		return false // ticking, but TS has been unreachable for too long
+	}
+	return clock.Since(w.clock, time.Unix(0, last)) <= limit
+}
+
+// Ready reports whether the worker has completed a claim round-trip to TS.
+func (w *Worker) Ready() bool {
+	return w.ready.Load()
+}
+
+// Starting reports whether the worker is still in its STARTUP_GRACE_SECONDS
+// window: Run has started, no claim has succeeded yet and the grace period
+// hasn't ended.
+func (w *Worker) Starting() bool {
+	until := w.graceUntil.Load()
+	return until != 0 && !w.ready.Load() && w.clock.Now().UnixNano() < until
+}
+
+// RegisterHandler adds a handler for a custom jobType.
+// Must be called before Run; duplicates return an error.
+func (w *Worker) RegisterHandler(jobType string, handler jobs.JobHandler) error {
+	return w.dispatcher.Register(jobType, handler)
+}
+
+// RegisterCheckpointingHandler adds a handler for a custom jobType that
+// emits progress checkpoints (see jobs.CheckpointHandler).
+// Must be called before Run; duplicates return an error.
+func (w *Worker) RegisterCheckpointingHandler(jobType string, handler jobs.CheckpointHandler) error {
+	return w.dispatcher.RegisterCheckpointing(jobType, handler)
+}
 
-	if envelope == nil {
-		// No jobs available — silent poll
+// flushSpans exports spans still queued at shutdown, bounded so an
+// unreachable collector can't hold up exit.
+func (w *Worker) flushSpans() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	w.tracer.Shutdown(ctx)
+}
+
+// SetResultSink replaces the result sink (default: AUDIT_ENABLED file sink
+// or no-op). Must be called before Run; the worker closes it on shutdown.
+func (w *Worker) SetResultSink(sink ResultSink) {
+	w.sink = sink
+}
+
+// RegisterSchema adds payload validation for a jobType. Must be called before Run.
+func (w *Worker) RegisterSchema(jobType string, schema jobs.PayloadSchema) {
+	w.dispatcher.SetSchema(jobType, schema)
+}
+
+// RegisterHandlerVersion declares the handler version a jobType's handler
+// provides; jobs whose envelope pins another handlerVersion fail with
+// HANDLER_VERSION_MISMATCH. Must be called before Run.
+func (w *Worker) RegisterHandlerVersion(jobType, version string) {
+	w.dispatcher.SetVersion(jobType, version)
+}
+
+// ProbeAPI checks that the TS API is reachable with the worker's client
+// settings (TLS, timeouts).
+func (w *Worker) ProbeAPI(ctx context.Context) error {
+	return w.apiClient.Probe(ctx)
+}
+
+// register announces this worker and the jobTypes it will run to TS. Failure is only
+// logged: polling works without it. Dry-run workers are not registered.
+func (w *Worker) register(ctx context.Context) error {
+	if w.config.DryRun {
+		return nil
+	}
+	var capabilities []string
+	for _, jobType := range w.dispatcher.RegisteredTypes() {
+		if w.routing.Load().JobTypeAllowed(jobType) {
+			capabilities = append(capabilities, jobType)
+		}
+	}
+	err := w.apiClient.Register(ctx, w.config.WorkerID, capabilities)
+	switch {
+	case err == nil:
+		w.logger.Info("worker registered", "capabilities", capabilities)
+	case errors.Is(err, client.ErrWorkerIDConflict) && !w.config.AllowDuplicateWorkerID:
+		return fmt.Errorf("WORKER_ID %q is already active on TS (another pod with the same ID?); "+
+			"set a unique WORKER_ID, or ALLOW_DUPLICATE_WORKER_ID=true to run anyway: %w", w.config.WorkerID, err)
+	case errors.Is(err, client.ErrWorkerIDConflict):
+		w.logger.Warn("worker ID already active on TS, continuing (ALLOW_DUPLICATE_WORKER_ID=true)")
+	case errors.Is(err, client.ErrRegisterUnsupported) && w.config.WorkerIDGenerated:
+		// TS can't vouch for uniqueness, so make the generated ID unique
+		w.renameWorker(fmt.Sprintf("%s-%08x", w.config.WorkerID, rand.Uint32()))
+	default:
+		w.logger.Warn("worker registration failed, continuing without it", logging.KeyError, err)
+	}
+	return nil
+}
+
+// renameWorker switches to a new WorkerID. Only valid before the poll
+// loop starts, while nothing else reads the ID.
+func (w *Worker) renameWorker(id string) {
+	w.logger.Info("worker registration not supported by TS, suffixing generated worker ID", "newWorkerId", id)
+	w.config.WorkerID = id
+	w.apiClient.SetWorkerID(id)
+	w.workerID.Store(&id)
+}
+
+// deregister removes this worker from the TS inventory on shutdown.
+func (w *Worker) deregister() {
+	if w.config.DryRun {
+		return
+	}
+	// The loop context is already cancelled; use a short-lived one
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := w.apiClient.Deregister(ctx, w.config.WorkerID); err != nil {
+		w.logger.Warn("worker deregistration failed", logging.KeyError, err)
 		return
 	}
+	w.logger.Info("worker deregistered")
+}
+
+// activeJobs returns the number of jobs currently executing or waiting in
+// the local queue.
+func (w *Worker) activeJobs() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.inflight) + len(w.queued)
+}
 
-	log.Printf("[Worker] Claimed job=%s type=%s worker=%s attempt=%d/%d",
-		envelope.Ticket.JobID, envelope.Ticket.JobType,
-		w.config.WorkerID, envelope.Attempts, envelope.MaxAttempts)
+// inflightJob is a running job and the cancel func for its TS-facing
+// context (heartbeats and result post).
+type inflightJob struct {
+	envelope  *client.JobEnvelope
+	cancel    context.CancelFunc
+	bytes     int64     // payload + result bytes counted against MAX_INFLIGHT_BYTES
+	startedAt time.Time // when admitJob took the slot
+}
 
+// drain waits on w.clock until no job is active or deadline passes.
+func (w *Worker) drain(deadline time.Time) {
+	if w.activeJobs() == 0 || !w.clock.Now().Before(deadline) {
+		return
+	}
+	ticker := w.clock.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for w.activeJobs() > 0 && w.clock.Now().Before(deadline) {
+		<-ticker.C()
+	}
+}
+
+// abandonInflight reports jobs still running after the drain window as
+// FAILED/WORKER_SHUTDOWN so TS can requeue them immediately, falling back
+// to a plain lease release. Each job's heartbeat is stopped first so it
+// cannot extend the lease after the failure is reported.
+func (w *Worker) abandonInflight(deadline time.Time) {
 	w.mu.Lock()
-	w.processing = true
+	pending := make([]*inflightJob, 0, len(w.inflight))
+	for _, job := range w.inflight {
+		pending = append(pending, job)
+	}
 	w.mu.Unlock()
 
-	defer func() {
+	if len(pending) == 0 {
+		return
+	}
+
+	// The loop context is already cancelled; bound reporting by the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), deadline.Sub(w.clock.Now()))
+	defer cancel()
+
+	for _, job := range pending {
+		job.cancel()
+
+		envelope := job.envelope
+		jobID := envelope.Ticket.JobID
+		_, err := w.reportFailure(ctx, envelope, "WORKER_SHUTDOWN", "worker shut down before the job finished")
+		if errors.Is(err, client.ErrResultAlreadyPosted) {
+			continue // the job got its own result out first
+		}
+		if err != nil {
+			w.logger.Error("shutdown failure report failed, releasing lease", logging.KeyJobID, jobID, logging.KeyError, err)
+			if err := w.apiClient.ReleaseJob(ctx, jobID, w.config.WorkerID, envelope.Attempts); err != nil {
+				w.logger.Error("lease release failed", logging.KeyJobID, jobID, logging.KeyError, err)
+				continue
+			}
+		}
+		w.walDone(jobID)
+		w.logger.Warn("abandoned unfinished job on shutdown",
+			logging.KeyJobID, jobID,
+			logging.KeyAttempt, envelope.Attempts,
+			logging.KeyStatus, "WORKER_SHUTDOWN")
+	}
+}
+
+// recordClaimFailure counts a failed claim and escalates once the streak
+// reaches CLAIM_FAILURE_THRESHOLD (Alive then reports unhealthy).
+func (w *Worker) recordClaimFailure() {
+	fails := w.claimFails.Add(1)
+	if fails == int64(w.config.ClaimFailureThreshold) {
+		w.logger.Error("claims keep failing, reporting unhealthy",
+			"consecutiveFailures", fails, "threshold", w.config.ClaimFailureThreshold, "inStartupGrace", w.Starting())
+	}
+}
+
+// resetClaimFailures clears the failure streak after a successful claim.
+func (w *Worker) resetClaimFailures() {
+	if fails := w.claimFails.Swap(0); fails >= int64(w.config.ClaimFailureThreshold) {
+		w.logger.Info("claims recovered, reporting healthy", "consecutiveFailures", fails)
+	}
+}
+
+// processNextJob handles one iteration of the polling loop:
+// claims as many jobs as there are free pool slots (plus free local queue
+// space) and starts or queues them. With no free slot it skips the claim
+// rather than locking jobs it can't start.
+// Reports whether a claim was sent and what it returned.
+func (w *Worker) processNextJob(ctx context.Context) pollResult {
+	free := w.tuner.current() - len(w.slots)
+	if w.queue != nil {
+		free += cap(w.queue) - w.queuedJobs()
+	}
+	// Saturated: skip the claim instead of locking jobs we can't start
+	saturated := free <= 0
+	saturation := 0.0
+	if saturated {
+		saturation = 1
+		if !w.wasSaturated {
+			w.logger.Debug("worker pool saturated, skipping claims", "active", w.activeJobs())
+		}
+		w.wasSaturated = true
+	}
+	w.metrics.SetGauge(metricPoolSaturated, saturation, nil)
+	if saturated || w.draining.Load() || w.recycling.Load() || w.restarting.Load() || w.clock.Now().Before(w.claimPausedUntil) {
+		return pollSkipped
+	}
+	if w.overBudget() {
+		w.logger.Debug("in-flight memory budget reached, skipping claim",
+			"inflightBytes", w.inflightBytes.Load(), "maxInflightBytes", w.config.MaxInflightBytes)
+		return pollSkipped
+	}
+
+	want := min(free, w.config.ClaimBatchSize)
+	if limit := w.config.MaxJobsBeforeExit; limit > 0 {
+		want = min(want, limit-int(w.jobsClaimed.Load()))
+	}
+	if want = w.claimTokens(want); want == 0 {
+		w.logger.Debug("claim rate limit reached, skipping tick")
+		return pollSkipped
+	}
+
+	if w.config.ClaimBusyHint {
+		w.apiClient.SetBusyHint(w.wasSaturated)
+	}
+	w.wasSaturated = false
+
+	envelopes, err := w.claim(ctx, want)
+	w.spendClaimTokens(len(envelopes))
+	if wait, ok := client.RetryAfter(err); ok {
+		wait = min(wait, w.config.ClaimRetryAfterMax)
+		w.claimPausedUntil = w.clock.Now().Add(wait)
+		w.logger.Warn("TS asked to back off, pausing claims", "retryAfter", wait.String(), logging.KeyError, err)
+		return pollFailed
+	}
+	if err != nil && ctx.Err() != nil {
+		return pollSkipped // shutdown aborted the claim (e.g. a held long-poll)
+	}
+	if errors.Is(err, client.ErrCircuitOpen) {
+		// The breaker logged the outage; still counts towards /healthz
+		w.recordClaimFailure()
+		return pollFailed
+	}
+	if errors.Is(err, client.ErrAuthFailed) {
+		// The client logged the credential failure; stop if configured to
+		w.recordClaimFailure()
+		if w.config.ExitOnAuthFailure {
+			w.authFailed.Store(true)
+		}
+		return pollFailed
+	}
+	if err != nil {
+		// A non-retryable 4xx (bad request) is a config problem that will
+		// not fix itself, so log it louder than a transient error.
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && !apiErr.Retryable() {
+			w.logger.Error("claim rejected by TS", logging.KeyStatus, apiErr.StatusCode, logging.KeyError, err)
+		} else {
+			w.logger.Warn("claim error", logging.KeyError, err)
+		}
+		w.recordClaimFailure()
+		return pollFailed
+	}
+	w.ready.Store(true)
+	w.resetClaimFailures()
+
+	// Empty slice = no jobs available — silent poll
+	seen := make(map[string]int, len(envelopes)) // jobId → attempt, this batch
+	for i := range envelopes {
+		envelope := &envelopes[i]
+		if w.duplicateClaim(ctx, envelope, seen) {
+			continue
+		}
+		seen[envelope.Ticket.JobID] = envelope.Attempts
+		w.metrics.IncrCounter(metricJobsClaimed, nil)
+		w.metrics.ObserveHistogram(metricJobAttempts, float64(envelope.Attempts), metrics.Labels{"jobType": envelope.Ticket.JobType})
+		w.logger.Info("claimed job",
+			logging.KeyJobID, envelope.Ticket.JobID,
+			logging.KeyJobType, envelope.Ticket.JobType,
+			logging.KeyAttempt, envelope.Attempts,
+			"maxAttempts", envelope.MaxAttempts,
+			"priority", envelope.Priority)
+
+		if w.jobTypeAtCap(envelope.Ticket.JobType) {
+			w.releaseAtCap(ctx, envelope)
+			continue
+		}
+		if !w.fitsBudget(envelope) {
+			w.releaseOverBudget(ctx, envelope)
+			continue
+		}
+		if w.recorder != nil {
+			w.recorder.record(envelope, w.clock.Now())
+		}
+		w.jobsClaimed.Add(1)
+		if w.queue != nil {
+			w.enqueueJob(ctx, envelope)
+		} else {
+			w.startJob(ctx, envelope)
+		}
+	}
+	if len(envelopes) == 0 {
+		return pollEmpty
+	}
+	return pollClaimed
+}
+
+// claim fetches up to max envelopes, using the batch endpoint when
+// max > 1 and falling back to single claim on older TS servers.
+// In dry-run mode a single job is peeked without taking a lease.
+func (w *Worker) claim(ctx context.Context, max int) ([]client.JobEnvelope, error) {
+	if w.config.DryRun {
+		envelope, err := w.apiClient.PeekJob(ctx, w.config.WorkerID)
+		if err != nil || envelope == nil {
+			return nil, err
+		}
+		return []client.JobEnvelope{*envelope}, nil
+	}
+
+	if w.longPolling() {
+		envelope, err := w.apiClient.ClaimJobLongPoll(ctx, w.config.WorkerID, int(w.config.LongPollWait/time.Second))
+		if !errors.Is(err, client.ErrLongPollUnsupported) {
+			if err != nil || envelope == nil {
+				return nil, err
+			}
+			return []client.JobEnvelope{*envelope}, nil
+		}
+		w.logger.Info("long-poll claim not supported by TS, falling back to ticker polling")
+		w.longPollUnsupported.Store(true)
+	}
+
+	if max > 1 && !w.batchUnsupported {
+		envelopes, err := w.apiClient.ClaimBatch(ctx, w.config.WorkerID, max)
+		if !errors.Is(err, client.ErrBatchUnsupported) {
+			return envelopes, err
+		}
+		w.logger.Info("claim-batch not supported by TS, falling back to single claim")
+		w.batchUnsupported = true
+	}
+
+	envelope, err := w.apiClient.ClaimJob(ctx, w.config.WorkerID)
+	if err != nil || envelope == nil {
+		return nil, err
+	}
+	return []client.JobEnvelope{*envelope}, nil
+}
+
+// duplicateClaim rejects an envelope whose jobId appeared earlier in the
+// same batch or is still executing or queued here (a TS bug or claim race), so the
+// job can't run twice and double-post its result. A duplicate carrying a
+// different attempt holds its own lease and is released; one with the
+// same attempt shares the lease of the copy being kept, and releasing it
+// would hand that lease back to TS, so it is only dropped.
+func (w *Worker) duplicateClaim(ctx context.Context, envelope *client.JobEnvelope, seen map[string]int) bool {
+	jobID := envelope.Ticket.JobID
+	attempt, dup := seen[jobID]
+	if !dup {
 		w.mu.Lock()
-		w.processing = false
+		if job, running := w.inflight[jobID]; running {
+			attempt, dup = job.envelope.Attempts, true
+		} else if job, queued := w.queued[jobID]; queued {
+			attempt, dup = job.envelope.Attempts, true
+		}
 		w.mu.Unlock()
-	}()
+	}
+	if !dup {
+		return false
+	}
+	w.metrics.IncrCounter(metricJobsDuplicate, nil)
+
+	if envelope.Attempts == attempt || w.config.DryRun {
+		w.logger.Warn("duplicate claim dropped", logging.KeyJobID, jobID, logging.KeyAttempt, envelope.Attempts)
+		return true
+	}
+	if err := w.apiClient.ReleaseJob(ctx, jobID, w.config.WorkerID, envelope.Attempts); err != nil {
+		w.logger.Error("lease release failed", logging.KeyJobID, jobID, logging.KeyError, err)
+		return true
+	}
+	w.logger.Warn("duplicate claim released",
+		logging.KeyJobID, jobID,
+		logging.KeyAttempt, envelope.Attempts,
+		"keptAttempt", attempt)
+	return true
+}
+
+// jobTypeAtCap reports whether jobType already has JOBTYPE_CONCURRENCY jobs
+// executing or waiting in the local queue. Only the poll loop starts jobs, so the answer holds until the
+// caller's startJob. Dry-run peeks hold no lease, so caps do not apply.
+func (w *Worker) jobTypeAtCap(jobType string) bool {
+	limit, ok := w.routing.Load().JobTypeConcurrency[jobType]
+	if !ok || w.config.DryRun {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	running := 0
+	for _, job := range w.inflight {
+		if job.envelope.Ticket.JobType == jobType {
+			running++
+		}
+	}
+	for _, job := range w.queued {
+		if job.envelope.Ticket.JobType == jobType {
+			running++
+		}
+	}
+	return running >= limit
+}
+
+// releaseAtCap hands a claimed job back to TS because its jobType is at
+// its concurrency cap, so another worker (or a later claim) can run it.
+func (w *Worker) releaseAtCap(ctx context.Context, envelope *client.JobEnvelope) {
+	jobID := envelope.Ticket.JobID
+	if err := w.apiClient.ReleaseJob(ctx, jobID, w.config.WorkerID, envelope.Attempts); err != nil {
+		w.logger.Error("lease release failed", logging.KeyJobID, jobID, logging.KeyError, err)
+		return
+	}
+	w.logger.Info("jobType at concurrency cap, released job",
+		logging.KeyJobID, jobID,
+		logging.KeyJobType, envelope.Ticket.JobType,
+		"cap", w.routing.Load().JobTypeConcurrency[envelope.Ticket.JobType])
+}
+
+// startJob occupies a pool slot and executes the envelope in a goroutine.
+// Callers must ensure a slot is free. The job runs on a context detached
+// from shutdown so heartbeats and the result post survive the drain window;
+// it is only cancelled if the job is abandoned at the shutdown deadline.
+func (w *Worker) startJob(ctx context.Context, envelope *client.JobEnvelope) {
+	if run := w.admitJob(ctx, envelope); run != nil {
+		go run()
+	}
+}
+
+// admitJob occupies a pool slot and records the envelope as in flight,
+// returning the func that executes it and then frees the slot. With a
+// local queue it returns nil if shutdown already released the job.
+func (w *Worker) admitJob(ctx context.Context, envelope *client.JobEnvelope) func() {
+	jobID := envelope.Ticket.JobID
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	w.slots <- struct{}{}
+	w.mu.Lock()
+	if w.queue != nil {
+		if _, queued := w.queued[jobID]; !queued {
+			w.mu.Unlock()
+			<-w.slots
+			cancel()
+			return nil
+		}
+		delete(w.queued, jobID)
+		w.metrics.SetGauge(metricQueueDepth, float64(len(w.queued)), nil)
+	}
+	payloadBytes := int64(len(envelope.Payload))
+	w.inflight[jobID] = &inflightJob{envelope: envelope, cancel: cancel, bytes: payloadBytes, startedAt: w.clock.Now()}
+	w.metrics.SetGauge(metricJobsActive, float64(len(w.inflight)), nil)
+	w.mu.Unlock()
+	w.addInflightBytes(payloadBytes)
 
-	if err := w.ProcessJob(ctx, envelope); err != nil {
-		log.Printf("[Worker] job=%s worker=%s status=ERROR attempt=%d err=%v",
-			envelope.Ticket.JobID, w.config.WorkerID, envelope.Attempts, err)
+	if w.wal != nil {
+		if err := w.wal.claim(envelope, w.clock.Now()); err != nil {
+			w.logger.Error("WAL write failed", logging.KeyJobID, jobID, logging.KeyError, err)
+		}
 	}
+
+	return func() {
+		defer func() {
+			cancel()
+			w.mu.Lock()
+			held := w.inflight[jobID].bytes
+			delete(w.inflight, jobID)
+			w.metrics.SetGauge(metricJobsActive, float64(len(w.inflight)), nil)
+			w.mu.Unlock()
+			w.addInflightBytes(-held)
+			<-w.slots
+		}()
+
+		if _, err := w.ProcessJob(jobCtx, envelope); err != nil {
+			w.logger.Error("job error",
+				logging.KeyJobID, envelope.Ticket.JobID,
+				logging.KeyTraceID, envelope.Ticket.TraceID,
+				logging.KeyAttempt, envelope.Attempts,
+				logging.KeyStatus, "ERROR",
+				logging.KeyError, err)
+			// Keep the WAL entry so a restart reports the attempt, unless TS
+			// already has the lease back or refused the result for good
+			if !errors.Is(err, errLeaseLost) && retryableDelivery(err) {
+				return
+			}
+		}
+		w.walDone(jobID)
+	}
+}
+
+// ProcessOutcome statuses.
+const (
+	OutcomeSucceeded = "SUCCEEDED"
+	OutcomeFailed    = "FAILED"
+	OutcomeDryRun    = "DRY_RUN"
+	OutcomeLeaseLost = "LEASE_LOST"
+	OutcomeAbandoned = "ABANDONED"
+	OutcomeCancelled = "CANCELLED"
+)
+
+// ProcessOutcome describes how ProcessJob finished, for embedders that need
+// more than an error. Status is what the worker decided for the job; a
+// non-nil error alongside it means the outcome could not be reported to TS.
+type ProcessOutcome struct {
+	Status    string // one of the Outcome* constants; "" if processing errored before a decision
+	ErrorCode string // reported errorCode for FAILED outcomes
+	LatencyMs int64  // handler run time (0 if it never ran)
+	Attempts  int
+
+	// What TS reported doing with the result (client.Disposition*; "" if
+	// TS didn't say, or the result was spooled or never posted)
+	Disposition string
 }
 
 // ProcessJob executes a single job envelope with heartbeat.
-func (w *Worker) ProcessJob(ctx context.Context, envelope *client.JobEnvelope) error {
+func (w *Worker) ProcessJob(ctx context.Context, envelope *client.JobEnvelope) (outcome ProcessOutcome, err error) {
 	ticket := &envelope.Ticket
 	traceID := ticket.TraceID
 	attempts := envelope.Attempts
 	maxAttempts := envelope.MaxAttempts
 
-	log.Printf("[Worker] Processing job=%s type=%s worker=%s trace=%s attempt=%d/%d",
-		ticket.JobID, ticket.JobType, w.config.WorkerID, traceID, attempts, maxAttempts)
+	// One root span per job (no-op unless OTEL_ENABLED), nested under the
+	// TS trace that enqueued it
+	span := w.tracer.Start(ticket.JobType, tracing.ParentFor(envelope.TraceParent, traceID))
+	span.SetAttribute("coreos.job_id", ticket.JobID)
+	span.SetAttribute("coreos.job_type", ticket.JobType)
+	span.SetAttribute("coreos.worker_id", w.config.WorkerID)
+	span.SetAttribute("coreos.attempt", attempts)
+	defer func() {
+		span.SetAttribute("coreos.status", outcome.Status)
+		if err != nil {
+			span.RecordError(cmp.Or(outcome.Status, "ERROR"), err.Error())
+		}
+		span.End()
+	}()
 
-	// 1. Verify ticket signature
-	if err := ticket.VerifySignature(w.publicKey); err != nil {
-		log.Printf("[Worker] job=%s worker=%s status=VERIFY_FAIL err=%v", ticket.JobID, w.config.WorkerID, err)
-		return w.reportFailure(ticket, "TICKET_INVALID", err.Error(), traceID, attempts)
+	outcome = ProcessOutcome{Attempts: attempts}
+	var logs *jobs.LogCapture // set once the handler runs
+	fail := func(errorCode, errorMsg string) (ProcessOutcome, error) {
+		outcome.Status = OutcomeFailed
+		outcome.ErrorCode = errorCode
+		span.RecordError(errorCode, errorMsg)
+		var resultData any
+		if logs != nil {
+			resultData, _ = jobs.AttachLogs(nil, logs.Tail())
+		}
+		disposition, err := w.reportFailureData(ctx, envelope, errorCode, errorMsg, resultData)
+		outcome.Disposition = disposition
+		return outcome, err
 	}
 
-	// 2. Verify expiry
-	if err := ticket.ValidateExpiry(); err != nil {
-		log.Printf("[Worker] job=%s worker=%s status=EXPIRED err=%v", ticket.JobID, w.config.WorkerID, err)
-		return w.reportFailure(ticket, "TICKET_EXPIRED", err.Error(), traceID, attempts)
+	jobLog := w.logger.With(
+		logging.KeyJobID, ticket.JobID,
+		logging.KeyJobType, ticket.JobType,
+		logging.KeyTraceID, traceID,
+		logging.KeyAttempt, attempts,
+	)
+	jobLog.Info("processing job", "maxAttempts", maxAttempts)
+
+	// 1. Reject oversized payloads before any decoding
+	if len(envelope.Payload) > w.config.MaxPayloadBytes {
+		err := fmt.Errorf("payload is %d bytes, limit is %d", len(envelope.Payload), w.config.MaxPayloadBytes)
+		jobLog.Warn("payload too large", logging.KeyStatus, "TOO_LARGE", logging.KeyError, err)
+		return fail("PAYLOAD_TOO_LARGE", err.Error())
 	}
 
-	// 3. Verify payload hash
-	if err := ticket.ValidatePayloadHash(envelope.Payload); err != nil {
-		log.Printf("[Worker] job=%s worker=%s status=HASH_MISMATCH err=%v", ticket.JobID, w.config.WorkerID, err)
-		return w.reportFailure(ticket, "PAYLOAD_MISMATCH", err.Error(), traceID, attempts)
+	// 2. Reject tickets missing identifiers or a well-formed payload hash
+	if err := ticket.ValidateFields(); err != nil {
+		jobLog.Warn("ticket malformed", logging.KeyStatus, "MALFORMED", logging.KeyError, err)
+		return fail("TICKET_MALFORMED", err.Error())
 	}
 
-	// 4. Start heartbeat goroutine
+	// 3. Require a nonce with enough entropy for replay protection
+	if err := ticket.ValidateNonce(w.config.NonceMinBytes); err != nil {
+		jobLog.Warn("ticket nonce invalid", logging.KeyStatus, "NONCE_FAIL", logging.KeyError, err)
+		return fail("NONCE_INVALID", err.Error())
+	}
+
+	// 4. Verify ticket signature
+	if err := w.verifyTicket(ctx, ticket); err != nil {
+		jobLog.Warn("ticket signature invalid", logging.KeyStatus, "VERIFY_FAIL", logging.KeyError, err)
+		return fail("TICKET_INVALID", err.Error())
+	}
+
+	// 5. Check the ticket grants the scopes this jobType requires
+	if err := ticket.ValidateScope(w.dispatcher.RequiredScope(ticket.JobType)); err != nil {
+		jobLog.Warn("ticket scope insufficient", logging.KeyStatus, "SCOPE_FAIL", logging.KeyError, err)
+		return fail("SCOPE_INSUFFICIENT", err.Error())
+	}
+
+	// 6. Enforce jobType allow/deny lists (TS filters too; this is defensive)
+	if !w.routing.Load().JobTypeAllowed(ticket.JobType) {
+		err := fmt.Errorf("jobType %s is not allowed on this worker", ticket.JobType)
+		jobLog.Warn("jobType not allowed", logging.KeyStatus, "NOT_ALLOWED", logging.KeyError, err)
+		return fail("JOBTYPE_NOT_ALLOWED", err.Error())
+	}
+
+	// 7. Require the handler version the job was enqueued for (if any)
+	if err := w.dispatcher.CheckVersion(ticket.JobType, envelope.HandlerVersion); err != nil {
+		jobLog.Warn("handler version mismatch", logging.KeyStatus, "VERSION_MISMATCH", logging.KeyError, err)
+		return fail("HANDLER_VERSION_MISMATCH", err.Error())
+	}
+
+	// 8. Verify expiry (as of the recorded claim time when replaying)
+	ticketNow := w.clock.Now()
+	if !w.localClaimedAt.IsZero() {
+		ticketNow = w.localClaimedAt
+	}
+	if err := ticket.ValidateExpiry(ticketNow); err != nil {
+		jobLog.Warn("ticket expired", logging.KeyStatus, "EXPIRED", logging.KeyError, err)
+		return fail("TICKET_EXPIRED", err.Error())
+	}
+
+	// 9. Drop jobs that waited in the queue past MAX_JOB_AGE_SECONDS
+	if err := ticket.ValidateAge(ticketNow, w.config.MaxJobAge); err != nil {
+		jobLog.Warn("job too old", logging.KeyStatus, "TOO_OLD", logging.KeyError, err)
+		return fail("JOB_TOO_OLD", err.Error())
+	}
+
+	// 10. Decrypt (aes-256-gcm), decode (gzip+base64) and verify hash over the plaintext
+	if err := envelope.DecryptPayload(w.payloadKey); err != nil {
+		jobLog.Warn("payload decrypt failed", logging.KeyStatus, "DECRYPT_FAIL", logging.KeyError, err)
+		return fail("PAYLOAD_DECRYPT_ERROR", err.Error())
+	}
+	payload, err := envelope.DecodePayload(w.config.MaxPayloadBytes)
+	if errors.Is(err, client.ErrPayloadTooLarge) {
+		jobLog.Warn("decompressed payload too large", logging.KeyStatus, "TOO_LARGE", logging.KeyError, err)
+		return fail("PAYLOAD_TOO_LARGE", err.Error())
+	}
+	if err != nil {
+		jobLog.Warn("payload decode failed", logging.KeyStatus, "DECODE_FAIL", logging.KeyError, err)
+		return fail("PAYLOAD_DECODE_ERROR", err.Error())
+	}
+	if err := ticket.ValidatePayloadHash(payload); err != nil {
+		// Contract drift or tampering: counted and logged on its own
+		w.metrics.IncrCounter(metricHashMismatch, metrics.Labels{"kind": "payload"})
+		var mismatch *contracts.HashMismatchError
+		if errors.As(err, &mismatch) {
+			jobLog.Error("payload hash mismatch", logging.KeyStatus, "HASH_MISMATCH",
+				"expectedHash", mismatch.Expected,
+				"computedHash", mismatch.Computed,
+				"payloadBytes", len(payload))
+		}
+		return fail("PAYLOAD_MISMATCH", err.Error())
+	}
+
+	// 11. Validate the payload against the jobType's schema (if registered)
+	if err := w.dispatcher.ValidatePayload(ticket.JobType, payload); err != nil {
+		jobLog.Warn("payload schema invalid", logging.KeyStatus, "SCHEMA_FAIL", logging.KeyError, err)
+		return fail("PAYLOAD_SCHEMA_INVALID", err.Error())
+	}
+
+	// Dry-run stops after validation (peeked jobs are seen repeatedly,
+	// so the nonce check is skipped too)
+	if w.config.DryRun {
+		jobLog.Info("dry-run: ticket valid, skipping dispatch", logging.KeyStatus, "DRY_RUN_OK")
+		outcome.Status = OutcomeDryRun
+		return outcome, nil
+	}
+
+	// 12. Reject replayed nonce (entry lives until the ticket's deadline,
+	// the same ExpiresAt+CLOCK_SKEW_MS that ValidateExpiry accepts)
+	if err := w.nonces.CheckAndStore(ticket.Nonce, ticket.Deadline().UnixMilli(), ticketNow); err != nil {
+		jobLog.Warn("ticket nonce replayed", logging.KeyStatus, "REPLAY", logging.KeyError, err)
+		return fail("TICKET_REPLAY", err.Error())
+	}
+
+	// 13. Start heartbeat goroutine
 	heartbeatCtx, heartbeatCancel := context.WithCancel(ctx)
 	defer heartbeatCancel()
-	go w.heartbeatLoop(heartbeatCtx, ticket.JobID)
+	leaseCtx, stopJob := context.WithCancelCause(ctx)
+	defer stopJob(nil)
+	progress := &jobProgress{}
+	if w.resultCapture == nil {
+		go w.heartbeatLoop(heartbeatCtx, ticket.JobID, traceID, heartbeatInterval(w.leaseMs(envelope)), stopJob, progress, jobLog)
+	}
 
-	// 5. Execute job
-	startedAt := time.Now().UnixMilli()
-	resultData, execErr := w.dispatcher.Dispatch(ticket.JobType, envelope.Payload, traceID)
-	finishedAt := time.Now().UnixMilli()
+	// 14. Execute job, cancelled on lease loss, TS request or ticket expiry
+	execCtx, execCancel := context.WithTimeout(leaseCtx, ticket.Deadline().Sub(ticketNow))
+	defer execCancel()
+	if w.config.MaxLogCaptureBytes > 0 {
+		execCtx, logs = jobs.CaptureLogs(execCtx, w.config.MaxLogCaptureBytes)
+	}
+	startedAt := w.clock.Now().UnixMilli()
+	resultData, execErr := w.dispatcher.DispatchWithCheckpoint(execCtx, ticket.JobType, payload, traceID, progress.checkpoint(jobLog))
+	finishedAt := w.clock.Now().UnixMilli()
+	outcome.LatencyMs = finishedAt - startedAt
+	w.metrics.ObserveHistogram(metricJobLatency, float64(outcome.LatencyMs), metrics.Labels{"jobType": ticket.JobType})
+	limit := w.tuner.observe(time.Duration(outcome.LatencyMs) * time.Millisecond)
+	w.metrics.SetGauge(metricConcurrency, float64(limit), nil)
 
-	// Stop heartbeat
+	// Stop heartbeat; a failed attempt's last checkpoint lets the retry resume
 	heartbeatCancel()
+	if execErr != nil && w.resultCapture == nil && leaseCtx.Err() == nil {
+		w.postCheckpoint(ctx, ticket.JobID, traceID, progress, jobLog)
+	}
+
+	// TS has likely requeued the job; posting a result would race the new owner
+	if errors.Is(context.Cause(leaseCtx), errLeaseLost) {
+		jobLog.Error("lease lost during execution, dropping result", logging.KeyStatus, "LEASE_LOST")
+		outcome.Status = OutcomeLeaseLost
+		return outcome, errLeaseLost
+	}
+
+	// TS asked us to stop; whatever the handler returned is not wanted
+	if cause := context.Cause(leaseCtx); errors.Is(cause, errJobCancelled) {
+		jobLog.Warn("job cancelled by TS", logging.KeyStatus, "CANCELLED", logging.KeyError, cause)
+		outcome.Status = OutcomeCancelled
+		outcome.ErrorCode = "JOB_CANCELLED"
+		disposition, err := w.reportCancelled(ctx, envelope, cause.Error())
+		outcome.Disposition = disposition
+		return outcome, err
+	}
+
+	// Abandoned at shutdown; abandonInflight reports the failure
+	if ctx.Err() != nil {
+		outcome.Status = OutcomeAbandoned
+		return outcome, ctx.Err()
+	}
+
+	// Any result now is outside the authorization window, even if the handler
+	// ignored cancellation and returned successfully
+	if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		err := fmt.Errorf("ticket expired at %d while the job was running", ticket.ExpiresAt)
+		jobLog.Warn("ticket expired mid-flight", logging.KeyStatus, "EXPIRED", logging.KeyError, err)
+		return fail("TICKET_EXPIRED_MIDFLIGHT", err.Error())
+	}
 
 	if execErr != nil {
-		log.Printf("[Worker] job=%s worker=%s status=EXEC_FAIL attempt=%d err=%v",
-			ticket.JobID, w.config.WorkerID, attempts, execErr)
-		return w.reportFailure(ticket, "EXECUTION_ERROR", execErr.Error(), traceID, attempts)
+		jobLog.Warn("job execution failed", logging.KeyStatus, "EXEC_FAIL", logging.KeyError, execErr)
+		return fail("EXECUTION_ERROR", execErr.Error())
 	}
 
-	// 6. Compute result hash
-	resultHash, err := contracts.ComputeResultHash(resultData)
+	// 15. Attach captured handler logs, compute the result hash over the
+	// full data, then apply MAX_RESULT_BYTES
+	if logs != nil {
+		var attached bool
+		if resultData, attached = jobs.AttachLogs(resultData, logs.Tail()); !attached {
+			jobLog.Debug("captured logs not attached: result data is not a JSON object or already has a logs field")
+		}
+	}
+	encoded, resultHash, err := contracts.EncodeResultData(resultData)
 	if err != nil {
-		return w.reportFailure(ticket, "HASH_ERROR", err.Error(), traceID, attempts)
+		return fail("HASH_ERROR", err.Error())
+	}
+	w.trackResultBytes(ticket.JobID, len(encoded))
+	truncated := w.config.MaxResultBytes > 0 && len(encoded) > w.config.MaxResultBytes
+	if truncated {
+		jobLog.Warn("result data too large, posting truncation summary",
+			logging.KeyStatus, "TRUNCATED", "resultBytes", len(encoded), "maxResultBytes", w.config.MaxResultBytes)
+		resultData = contracts.TruncatedResultSummary(len(encoded), w.config.MaxResultBytes)
+		encoded = nil // the summary is small enough for a single-shot post
+		// v1 doesn't sign the truncated flag, so the hash covers the posted
+		// summary (and its truncated marker) instead of the full data
+		if contracts.ResultSignatureVersion < contracts.ResultSignatureV2 {
+			if resultHash, err = contracts.ComputeResultHash(resultData); err != nil {
+				return fail("HASH_ERROR", err.Error())
+			}
+		}
 	}
 
-	// 7. Build and sign result
+	// 16. Build and sign result
 	result := &contracts.JobResult{
 		JobID:      ticket.JobID,
 		Status:     "SUCCEEDED",
@@ -193,51 +1351,285 @@ func (w *Worker) ProcessJob(ctx context.Context, envelope *client.JobEnvelope) e
 		TraceID:  traceID,
 		WorkerID: w.config.WorkerID,
 	}
+	if contracts.ResultSignatureVersion >= contracts.ResultSignatureV2 {
+		result.Truncated = truncated
+	}
 
 	if err := result.Sign(w.config.HMACSecret); err != nil {
-		return err
+		return outcome, err
 	}
 
-	// 8. Post result to TS
-	if err := w.apiClient.PostResult(result); err != nil {
-		log.Printf("[Worker] job=%s worker=%s status=POST_FAIL err=%v", ticket.JobID, w.config.WorkerID, err)
+	outcome.Status = OutcomeSucceeded
+
+	// 17. Post result to TS (chunked for large result data)
+	disposition, err := w.postResult(ctx, result, encoded)
+	if err != nil {
+		jobLog.Error("result post failed", logging.KeyStatus, "POST_FAIL", logging.KeyError, err)
+		return outcome, err
+	}
+	outcome.Disposition = disposition
+	w.metrics.IncrCounter(metricJobsSucceeded, nil)
+
+	jobLog.Info("job completed", logging.KeyStatus, "COMPLETED", "latencyMs", outcome.LatencyMs)
+	return outcome, nil
+}
+
+// postResult posts a signed success result (retrying, then spooling, on
+// transient failures) and records it in the sink once TS has it. Returns
+// TS's disposition ("" if not reported or spooled).
+func (w *Worker) postResult(ctx context.Context, result *contracts.JobResult, encoded []byte) (string, error) {
+	if w.resultCapture != nil {
+		w.resultCapture(result)
+		return "", nil
+	}
+	var disposition string
+	posted, err := w.deliverResult(ctx, result, func(ctx context.Context) (err error) {
+		disposition, err = w.sendResult(ctx, result, encoded)
 		return err
+	})
+	if client.ResultHashMismatch(err) {
+		w.metrics.IncrCounter(metricHashMismatch, metrics.Labels{"kind": "result"})
+		w.logger.Error("TS rejected result: hash mismatch",
+			logging.KeyJobID, result.JobID,
+			logging.KeyTraceID, result.TraceID,
+			"expectedHash", result.ResultHash,
+			"truncated", result.Truncated,
+			logging.KeyError, err)
+	}
+	if err != nil {
+		return "", err
 	}
+	if posted {
+		w.recordResult(result)
+		w.logDisposition(result, disposition)
+	}
+	return disposition, nil
+}
 
-	log.Printf("[Worker] job=%s worker=%s status=COMPLETED attempt=%d latency=%dms",
-		ticket.JobID, w.config.WorkerID, attempts, finishedAt-startedAt)
-	return nil
+// logDisposition logs what TS reported doing with a posted result.
+// Dead-lettering is logged as a warning; TS that report nothing log nothing.
+func (w *Worker) logDisposition(result *contracts.JobResult, disposition string) {
+	if disposition == "" {
+		return
+	}
+	level := slog.LevelInfo
+	if disposition == client.DispositionDeadLettered {
+		level = slog.LevelWarn
+	}
+	args := []any{
+		logging.KeyJobID, result.JobID,
+		logging.KeyTraceID, result.TraceID,
+		logging.KeyStatus, result.Status,
+		"disposition", disposition,
+	}
+	if result.ErrorCode != "" {
+		args = append(args, "errorCode", result.ErrorCode)
+	}
+	w.logger.Log(context.Background(), level, "TS result disposition", args...)
 }
 
-// heartbeatLoop sends heartbeat every 10s until context is cancelled.
-func (w *Worker) heartbeatLoop(ctx context.Context, jobID string) {
-	ticker := time.NewTicker(10 * time.Second)
+// sendResult uploads a success result and returns TS's disposition.
+// Result data larger than RESULT_STREAM_THRESHOLD_BYTES is streamed in
+// chunks; if TS lacks the stream endpoint the single-shot post is used
+// for all later results.
+func (w *Worker) sendResult(ctx context.Context, result *contracts.JobResult, encoded []byte) (string, error) {
+	if len(encoded) <= w.config.ResultStreamThreshold || w.streamUnsupported.Load() {
+		return w.apiClient.PostResult(ctx, result)
+	}
+
+	data := result.ResultData
+	result.ResultData = nil
+	disposition, err := w.apiClient.PostResultStream(ctx, result, encoded)
+	if !errors.Is(err, client.ErrStreamUnsupported) {
+		if err != nil {
+			result.ResultData = data // keep it for a retry or the spool
+		}
+		return disposition, err
+	}
+
+	w.logger.Info("result-stream not supported by TS, falling back to single-shot result post")
+	w.streamUnsupported.Store(true)
+	result.ResultData = data
+	return w.apiClient.PostResult(ctx, result)
+}
+
+// Heartbeat interval bounds. Without a lease duration from TS the
+// historical fixed interval is used.
+const (
+	defaultHeartbeatInterval = 10 * time.Second
+	minHeartbeatInterval     = 1 * time.Second
+	maxHeartbeatInterval     = 60 * time.Second
+)
+
+// leaseMs returns the lease TS reported for the job, or else the visibility
+// timeout the worker requested for its jobType (0 = unknown).
+func (w *Worker) leaseMs(envelope *client.JobEnvelope) int64 {
+	if envelope.LeaseDurationMs > 0 {
+		return envelope.LeaseDurationMs
+	}
+	return w.config.VisibilityTimeoutFor(envelope.Ticket.JobType).Milliseconds()
+}
+
+// heartbeatInterval returns one third of the lease (so two heartbeats can
+// be lost before it expires), clamped to [min, max].
+func heartbeatInterval(leaseMs int64) time.Duration {
+	if leaseMs <= 0 {
+		return defaultHeartbeatInterval
+	}
+	interval := time.Duration(leaseMs) * time.Millisecond / 3
+	return min(max(interval, minHeartbeatInterval), maxHeartbeatInterval)
+}
+
+// errLeaseLost marks a job whose lease is presumed expired after too many
+// consecutive heartbeat failures.
+var errLeaseLost = errors.New("lease lost: consecutive heartbeat failures")
+
+// errJobCancelled marks a job TS asked to cancel via a heartbeat response.
+var errJobCancelled = errors.New("cancelled by TS")
+
+// heartbeatLoop sends a heartbeat every interval until context is cancelled,
+// skipping beats while TS has asked to back off (429 Retry-After, capped at
+// one interval so the pause stays a fraction of the lease), and
+// posts the job's pending checkpoint after each accepted beat. It stops
+// the job with errJobCancelled when TS asks for cancellation, or with
+// errLeaseLost after HEARTBEAT_FAILURE_THRESHOLD consecutive failures.
+func (w *Worker) heartbeatLoop(ctx context.Context, jobID, traceID string, interval time.Duration, stopJob context.CancelCauseFunc, progress *jobProgress, logger *slog.Logger) {
+	ticker := w.clock.NewTicker(interval)
 	defer ticker.Stop()
 
+	var pausedUntil time.Time
+	failures := 0
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			if err := w.apiClient.Heartbeat(jobID, w.config.WorkerID); err != nil {
-				log.Printf("[Worker] job=%s heartbeat error: %v", jobID, err)
-			} else {
-				log.Printf("[Worker] job=%s heartbeat sent", jobID)
+		case <-ticker.C():
+			if w.clock.Now().Before(pausedUntil) {
+				continue
+			}
+			hb, err := w.apiClient.Heartbeat(ctx, jobID, w.config.WorkerID, traceID)
+			if err == nil {
+				failures = 0
+				w.metrics.IncrCounter(metricHeartbeatsSent, nil)
+				logger.Debug("heartbeat sent")
+				if hb.Cancel {
+					stopJob(fmt.Errorf("%w: %s", errJobCancelled, cmp.Or(hb.Reason, "no reason given")))
+					return
+				}
+				w.postCheckpoint(ctx, jobID, traceID, progress, logger)
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			failures++
+			w.metrics.IncrCounter(metricHeartbeatsFailed, nil)
+			if wait, ok := client.RetryAfter(err); ok {
+				pausedUntil = w.clock.Now().Add(min(wait, interval))
+			}
+			logger.Warn("heartbeat error", "consecutiveFailures", failures, logging.KeyError, err)
+
+			if threshold := w.config.HeartbeatFailureThreshold; threshold > 0 && failures >= threshold {
+				logger.Error("heartbeat failure threshold reached, treating lease as lost",
+					"consecutiveFailures", failures, logging.KeyStatus, "LEASE_LOST")
+				stopJob(errLeaseLost)
+				return
 			}
 		}
 	}
 }
 
-// reportFailure sends a FAILED result back to TS.
-func (w *Worker) reportFailure(ticket *contracts.JobTicket, errorCode, errorMsg, traceID string, attempts int) error {
-	now := time.Now().UnixMilli()
-
+// reportCancelled sends a FAILED/JOB_CANCELLED result for a job TS asked
+// to stop, so TS can close it out (TS only accepts SUCCEEDED or FAILED).
+// Under v2 signatures GiveUp is set so it is not requeued. Returns TS's
+// disposition, like postResult.
+func (w *Worker) reportCancelled(ctx context.Context, envelope *client.JobEnvelope, reason string) (string, error) {
+	ticket := &envelope.Ticket
+	now := w.clock.Now().UnixMilli()
 	result := &contracts.JobResult{
 		JobID:        ticket.JobID,
 		Status:       "FAILED",
 		StartedAt:    now,
 		FinishedAt:   now,
 		ResultHash:   contracts.ComputePayloadHash(""),
+		ErrorCode:    "JOB_CANCELLED",
+		ErrorMessage: reason,
+		Metrics: contracts.JobMetrics{
+			Attempts: envelope.Attempts,
+		},
+		TraceID:  ticket.TraceID,
+		WorkerID: w.config.WorkerID,
+	}
+	// giveUp is only signed under v2, so v1 results don't carry it
+	if contracts.ResultSignatureVersion >= contracts.ResultSignatureV2 {
+		result.GiveUp = true
+	}
+	if err := result.Sign(w.config.HMACSecret); err != nil {
+		return "", err
+	}
+	w.metrics.IncrCounter(metricJobsCancelled, nil)
+
+	return w.deliverReport(ctx, result)
+}
+
+// retryAfter is the retry delay suggested for a failed attempt: the
+// jobType's RetryAfter if set, else the exponential RETRY_BACKOFF_BASE_MS
+// backoff unless this was the last attempt.
+func (w *Worker) retryAfter(policy jobs.RetryPolicy, envelope *client.JobEnvelope) time.Duration {
+	if policy.RetryAfter > 0 {
+		return policy.RetryAfter
+	}
+	attempts := envelope.Attempts
+	if policy.GiveUp(attempts) || (envelope.MaxAttempts > 0 && attempts >= envelope.MaxAttempts) {
+		return 0
+	}
+	return jobs.Backoff(w.config.RetryBackoffBase, w.config.RetryBackoffMax, attempts)
+}
+
+// reportFailure sends a FAILED result back to TS, with retry hints from
+// the jobType's retry policy (see retryAfter) when results are signed with
+// v2. On the terminal attempt a dead-letter notification is also emitted
+// (if configured). In dry-run mode it only logs.
+// Returns TS's disposition, like postResult.
+func (w *Worker) reportFailure(ctx context.Context, envelope *client.JobEnvelope, errorCode, errorMsg string) (string, error) {
+	return w.reportFailureData(ctx, envelope, errorCode, errorMsg, nil)
+}
+
+// reportFailureData is reportFailure with result data (e.g. captured
+// handler logs) attached to the FAILED result.
+func (w *Worker) reportFailureData(ctx context.Context, envelope *client.JobEnvelope, errorCode, errorMsg string, resultData any) (string, error) {
+	ticket := &envelope.Ticket
+	traceID := ticket.TraceID
+	attempts := envelope.Attempts
+
+	if w.config.DryRun {
+		w.logger.Warn("dry-run: ticket rejected, not reporting",
+			logging.KeyJobID, ticket.JobID,
+			logging.KeyTraceID, traceID,
+			"errorCode", errorCode,
+			"errorMessage", errorMsg)
+		return "", nil
+	}
+
+	now := w.clock.Now().UnixMilli()
+	policy := w.dispatcher.PolicyFor(ticket.JobType)
+	resultHash := contracts.ComputePayloadHash("")
+	if resultData != nil {
+		hash, err := contracts.ComputeResultHash(resultData)
+		if err != nil {
+			return "", err
+		}
+		resultHash = hash
+	}
+
+	result := &contracts.JobResult{
+		JobID:        ticket.JobID,
+		Status:       "FAILED",
+		StartedAt:    now,
+		FinishedAt:   now,
+		ResultHash:   resultHash,
+		ResultData:   resultData,
 		ErrorCode:    errorCode,
 		ErrorMessage: errorMsg,
 		Metrics: contracts.JobMetrics{
@@ -248,9 +1640,54 @@ func (w *Worker) reportFailure(ticket *contracts.JobTicket, errorCode, errorMsg,
 		WorkerID: w.config.WorkerID,
 	}
 
+	// The retry hints are only signed under v2, so v1 results don't carry them
+	giveUp := policy.GiveUp(attempts)
+	if contracts.ResultSignatureVersion >= contracts.ResultSignatureV2 {
+		result.GiveUp = giveUp
+		result.RetryAfterMs = w.retryAfter(policy, envelope).Milliseconds()
+	}
+
 	if err := result.Sign(w.config.HMACSecret); err != nil {
-		return err
+		return "", err
+	}
+	if w.resultCapture != nil {
+		w.resultCapture(result)
+		return "", nil
+	}
+
+	w.metrics.IncrCounter(metricJobsFailed, metrics.Labels{"errorCode": errorCode})
+
+	terminal := giveUp || (envelope.MaxAttempts > 0 && attempts >= envelope.MaxAttempts)
+	if terminal && w.deadLetter != nil && !workerStopCodes[errorCode] {
+		w.deadLetter.notify(deadLetterEvent{
+			JobID:        ticket.JobID,
+			JobType:      ticket.JobType,
+			TraceID:      traceID,
+			WorkerID:     w.config.WorkerID,
+			ErrorCode:    errorCode,
+			ErrorMessage: errorMsg,
+			Attempts:     attempts,
+			MaxAttempts:  envelope.MaxAttempts,
+		})
 	}
 
-	return w.apiClient.PostResult(result)
+	return w.deliverReport(ctx, result)
+}
+
+// deliverReport posts a FAILED or CANCELLED result (retrying, then
+// spooling) and records it once TS has it.
+func (w *Worker) deliverReport(ctx context.Context, result *contracts.JobResult) (string, error) {
+	var disposition string
+	posted, err := w.deliverResult(ctx, result, func(ctx context.Context) (err error) {
+		disposition, err = w.apiClient.PostResult(ctx, result)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if posted {
+		w.recordResult(result)
+		w.logDisposition(result, disposition)
+	}
+	return disposition, nil
 }
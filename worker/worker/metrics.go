@@ -0,0 +1,94 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Worker Metrics (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Job lifecycle metrics. Recorded through metrics.Metrics; by default a
+// Prometheus adapter exposed on /metrics when METRICS_ENABLED=true, or any
+// backend passed to SetMetrics.
+
+package worker
+
+import (
+	"net/http"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/jobs"
+	"github.com/gemimi2525-star/super-platform/worker/metrics"
+)
+
+// Metric names recorded by the polling loop.
+const (
+	metricJobsClaimed      = "worker_jobs_claimed_total"
+	metricJobsSucceeded    = "worker_jobs_succeeded_total"
+	metricJobsFailed       = "worker_jobs_failed_total"
+	metricJobsCancelled    = "worker_jobs_cancelled_total"
+	metricJobsDuplicate    = "worker_jobs_duplicate_total"
+	metricHashMismatch     = "worker_hash_mismatch_total"
+	metricTicketKeyRefresh = "worker_ticket_key_refresh_total"
+	metricJobsActive       = "worker_jobs_active"
+	metricInflightBytes    = "worker_inflight_bytes"
+	metricQueueDepth       = "worker_local_queue_depth"
+	metricConcurrency      = "worker_concurrency_limit"
+	metricPoolSaturated    = "worker_pool_saturated"
+	metricJobLatency       = "worker_job_latency_ms"
+	metricJobAttempts      = "worker_job_attempts"
+	metricHeartbeatsSent   = "worker_heartbeats_sent_total"
+	metricHeartbeatsFailed = "worker_heartbeats_failed_total"
+	metricCheckpoints      = "worker_checkpoints_total"
+)
+
+// attemptBuckets are histogram upper bounds for the attempt a job was claimed on.
+var attemptBuckets = []float64{1, 2, 3, 5, 10}
+
+// newPrometheusMetrics declares the worker, dispatcher and API client
+// families on a Prometheus adapter.
+func newPrometheusMetrics() *metrics.Prometheus {
+	p := metrics.NewPrometheus()
+	p.DeclareCounter(metricJobsClaimed, "Jobs claimed from TS.")
+	p.DeclareCounter(metricJobsSucceeded, "Jobs completed and reported as SUCCEEDED.")
+	p.DeclareCounter(metricJobsFailed, "Jobs reported as FAILED, by error code.", "errorCode")
+	p.DeclareCounter(metricJobsCancelled, "Jobs stopped at TS's request and reported as FAILED/JOB_CANCELLED.")
+	p.DeclareCounter(metricJobsDuplicate, "Claimed envelopes dropped as duplicates of a job already claimed or executing.")
+	p.DeclareCounter(metricHashMismatch, "Payload hashes that didn't match the ticket, and results TS rejected for a hash mismatch.", "kind")
+	p.DeclareGauge(metricJobsActive, "Jobs currently executing.")
+	p.DeclareCounter(metricTicketKeyRefresh, "Ticket signing key fetches from TS, by result.", "result")
+	p.DeclareGauge(metricInflightBytes, "Approximate payload and result bytes held by executing jobs.")
+	p.DeclareGauge(metricQueueDepth, "Claimed jobs waiting in the worker-local queue for an executor.")
+	p.DeclareGauge(metricConcurrency, "Current limit on concurrently executing jobs.")
+	p.DeclareGauge(metricPoolSaturated, "1 while every executor slot (and local queue slot) is taken, so claims are skipped.")
+	p.DeclareHistogram(metricJobLatency, "Handler execution latency in milliseconds.", metrics.DefaultLatencyBuckets)
+	p.DeclareHistogram(metricJobAttempts, "Attempt number of claimed jobs.", attemptBuckets)
+
+	p.DeclareCounter(metricHeartbeatsSent, "Lease heartbeats accepted by TS.")
+	p.DeclareCounter(metricHeartbeatsFailed, "Lease heartbeats that failed.")
+	p.DeclareCounter(metricCheckpoints, "Handler progress checkpoints posted to TS, by result (ok or error).", "result")
+
+	p.DeclareCounter(jobs.MetricDispatch, "Handler invocations, by jobType and result.", "jobType", "result")
+	p.DeclareCounter(client.MetricRequests, "Requests to TS, by path and status code.", "path", "status")
+	p.DeclareCounter(client.MetricAuthFailures, "Requests TS rejected as unauthenticated (401, or 403 from claim/register), by path.", "path")
+	p.DeclareCounter(client.MetricClaimResponses, "Claim responses by path and result: job, empty_200 or empty_204 (legacy).", "path", "result")
+	p.DeclareHistogram(client.MetricRequestLatency, "TS request latency in milliseconds.", metrics.DefaultLatencyBuckets)
+	p.DeclareGauge(client.MetricBreakerState, "TS circuit breaker state: 0 closed, 1 half-open, 2 open.")
+	return p
+}
+
+// SetMetrics routes all worker, dispatcher and API client metrics to m
+// (e.g. a StatsD adapter) instead of the built-in Prometheus registry.
+// Must be called before Run; /metrics then serves 404.
+func (w *Worker) SetMetrics(m metrics.Metrics) {
+	w.metrics = m
+	w.prometheus = nil
+	w.dispatcher.SetMetrics(m)
+	w.apiClient.SetMetrics(m)
+	if w.ticketKeys != nil {
+		w.ticketKeys.metrics = m
+	}
+}
+
+// MetricsHandler returns the Prometheus /metrics handler.
+func (w *Worker) MetricsHandler() http.Handler {
+	if w.prometheus == nil {
+		return http.NotFoundHandler()
+	}
+	return w.prometheus.Handler()
+}
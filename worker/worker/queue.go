@@ -0,0 +1,149 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Local Job Queue (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// With LOCAL_QUEUE_DEPTH > 0 the poll loop claims ahead of execution: up to
+// the free pool slots plus the free queue space. Claimed jobs wait in a
+// buffered channel and MAX_CONCURRENCY executor goroutines run them, one
+// job each at a time, so execution never waits on a claim round-trip.
+//
+// Nothing heartbeats a queued job, so an executor releases one that has
+// waited half its lease rather than start it with the lease about to
+// expire. On shutdown every job still queued is released to TS at once
+// instead of waiting out the drain window.
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/clock"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// queuedJob is a claimed job waiting for an executor.
+type queuedJob struct {
+	envelope   *client.JobEnvelope
+	enqueuedAt time.Time
+}
+
+// queuedJobs returns the number of jobs in the local queue, including one
+// an executor has taken but not yet started.
+func (w *Worker) queuedJobs() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.queued)
+}
+
+// enqueueJob hands a claimed job to the executors. processNextJob claims no
+// more than fits, so this only waits while an executor picks up a job;
+// a job still unsent at shutdown stays in w.queued and is released there.
+func (w *Worker) enqueueJob(ctx context.Context, envelope *client.JobEnvelope) {
+	w.mu.Lock()
+	w.queued[envelope.Ticket.JobID] = &queuedJob{envelope: envelope, enqueuedAt: w.clock.Now()}
+	w.metrics.SetGauge(metricQueueDepth, float64(len(w.queued)), nil)
+	w.mu.Unlock()
+
+	select {
+	case w.queue <- envelope:
+	case <-ctx.Done():
+	}
+}
+
+// executor runs queued jobs until the loop context is cancelled. Executors
+// numbered at or above the tuner's current limit sit idle, so AIMD backoff
+// still bounds how many jobs run at once.
+func (w *Worker) executor(ctx context.Context, id int) {
+	for {
+		if id >= w.tuner.current() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(250 * time.Millisecond):
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case envelope := <-w.queue:
+			if w.queuedTooLong(ctx, envelope) {
+				continue
+			}
+			if run := w.admitJob(ctx, envelope); run != nil {
+				run()
+			}
+		}
+	}
+}
+
+// queuedTooLong releases a job that waited in the queue for half its lease
+// or more, reporting whether it did.
+func (w *Worker) queuedTooLong(ctx context.Context, envelope *client.JobEnvelope) bool {
+	jobID := envelope.Ticket.JobID
+	leaseMs := w.leaseMs(envelope)
+
+	w.mu.Lock()
+	job, ok := w.queued[jobID]
+	if !ok {
+		w.mu.Unlock()
+		return true // released by shutdown
+	}
+	waited := clock.Since(w.clock, job.enqueuedAt)
+	if leaseMs <= 0 || waited.Milliseconds() < leaseMs/2 || w.config.DryRun {
+		w.mu.Unlock()
+		return false
+	}
+	delete(w.queued, jobID)
+	w.metrics.SetGauge(metricQueueDepth, float64(len(w.queued)), nil)
+	w.mu.Unlock()
+
+	if err := w.apiClient.ReleaseJob(ctx, jobID, w.config.WorkerID, envelope.Attempts); err != nil {
+		w.logger.Error("lease release failed", logging.KeyJobID, jobID, logging.KeyError, err)
+		return true
+	}
+	w.logger.Warn("job waited too long in the local queue, released",
+		logging.KeyJobID, jobID,
+		logging.KeyJobType, envelope.Ticket.JobType,
+		"queuedMs", waited.Milliseconds(),
+		"leaseMs", leaseMs)
+	return true
+}
+
+// releaseQueued hands every job still in the local queue back to TS on
+// shutdown so another worker can run it now. Dry-run peeks hold no lease
+// and are just dropped.
+func (w *Worker) releaseQueued() {
+	w.mu.Lock()
+	pending := make([]*client.JobEnvelope, 0, len(w.queued))
+	for _, job := range w.queued {
+		pending = append(pending, job.envelope)
+	}
+	clear(w.queued)
+	w.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	w.metrics.SetGauge(metricQueueDepth, 0, nil)
+	if w.config.DryRun {
+		return
+	}
+
+	// The loop context is already cancelled; use a short-lived one
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	released := 0
+	for _, envelope := range pending {
+		jobID := envelope.Ticket.JobID
+		if err := w.apiClient.ReleaseJob(ctx, jobID, w.config.WorkerID, envelope.Attempts); err != nil {
+			w.logger.Error("lease release failed", logging.KeyJobID, jobID, logging.KeyError, err)
+			continue
+		}
+		released++
+	}
+	w.logger.Info("released queued jobs on shutdown", "released", released, "queued", len(pending))
+}
@@ -0,0 +1,40 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Worker Claim Rate Limiter (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Token bucket gating job claims (MAX_JOBS_PER_SECOND, CLAIM_BURST) so a
+// backlog drain cannot overload shared downstream services.
+// Non-blocking: when the bucket is empty the poll tick simply skips claiming.
+// Tokens are only spent on jobs TS actually hands out.
+
+package worker
+
+import (
+	"golang.org/x/time/rate"
+
+	"github.com/gemimi2525-star/super-platform/worker/config"
+)
+
+// newClaimLimiter returns a full bucket for cfg, or nil when claims are
+// unlimited.
+func newClaimLimiter(cfg *config.Config) *rate.Limiter {
+	if cfg.MaxJobsPerSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(cfg.MaxJobsPerSecond), cfg.ClaimBurst)
+}
+
+// claimTokens returns how many of want claims the bucket allows now.
+func (w *Worker) claimTokens(want int) int {
+	if w.limiter == nil {
+		return want
+	}
+	return min(want, int(w.limiter.TokensAt(w.clock.Now())))
+}
+
+// spendClaimTokens takes a token for each of n claimed jobs.
+func (w *Worker) spendClaimTokens(n int) {
+	if w.limiter != nil && n > 0 {
+		w.limiter.AllowN(w.clock.Now(), n)
+	}
+}
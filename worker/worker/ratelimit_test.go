@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/clock"
+	"github.com/gemimi2525-star/super-platform/worker/config"
+)
+
+func TestClaimLimiterFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.Now())
+	w := &Worker{
+		clock:   fake,
+		limiter: newClaimLimiter(&config.Config{MaxJobsPerSecond: 2, ClaimBurst: 4}),
+	}
+
+	if got := w.claimTokens(10); got != 4 {
+		t.Fatalf("full bucket: claimTokens(10) = %d, want the burst of 4", got)
+	}
+	// Only claimed jobs spend tokens: TS handed out 1 of the 4 asked for
+	w.spendClaimTokens(1)
+	if got := w.claimTokens(10); got != 3 {
+		t.Fatalf("after 1 claimed: claimTokens(10) = %d, want 3", got)
+	}
+	w.spendClaimTokens(3)
+	if got := w.claimTokens(10); got != 0 {
+		t.Fatalf("empty bucket: claimTokens(10) = %d, want 0", got)
+	}
+
+	fake.Advance(time.Second)
+	if got := w.claimTokens(10); got != 2 {
+		t.Fatalf("after 1s at 2/s: claimTokens(10) = %d, want 2", got)
+	}
+	fake.Advance(time.Minute)
+	if got := w.claimTokens(10); got != 4 {
+		t.Fatalf("after a long idle: claimTokens(10) = %d, want the burst of 4", got)
+	}
+}
+
+func TestClaimLimiterUnlimited(t *testing.T) {
+	w := &Worker{clock: clock.Real, limiter: newClaimLimiter(&config.Config{})}
+	if w.limiter != nil {
+		t.Fatal("newClaimLimiter() with MAX_JOBS_PER_SECOND unset is not nil")
+	}
+	if got := w.claimTokens(7); got != 7 {
+		t.Fatalf("claimTokens(7) = %d, want 7", got)
+	}
+	w.spendClaimTokens(7) // no-op
+}
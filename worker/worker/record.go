@@ -0,0 +1,64 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Envelope Recording (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// With RECORD_ENVELOPES_DIR set, every claimed envelope is written to its
+// own JSON file before processing starts (signed ticket, payload still
+// encoded/encrypted as TS sent it), for incident forensics. Files are
+// named <claimedAtMs>-<jobId>-a<attempt>.json so they sort in claim order
+// and can be re-run with --replay <dir> or --run-file <path>. Recording
+// is best-effort: a write failure is logged and the job runs as usual.
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// envelopeRecorder writes claimed envelopes to a directory.
+type envelopeRecorder struct {
+	dir    string
+	logger *slog.Logger
+}
+
+// newEnvelopeRecorder creates dir if needed.
+func newEnvelopeRecorder(dir string, logger *slog.Logger) (*envelopeRecorder, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create RECORD_ENVELOPES_DIR: %w", err)
+	}
+	return &envelopeRecorder{dir: dir, logger: logger}, nil
+}
+
+// record writes one envelope claimed at claimedAt. It must run before
+// ProcessJob, which decrypts the payload in place.
+func (r *envelopeRecorder) record(envelope *client.JobEnvelope, claimedAt time.Time) {
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		r.logger.Warn("envelope record failed", logging.KeyJobID, envelope.Ticket.JobID, logging.KeyError, err)
+		return
+	}
+	name := fmt.Sprintf("%d-%s-a%d.json", claimedAt.UnixMilli(), safeFileName(envelope.Ticket.JobID), envelope.Attempts)
+	if err := os.WriteFile(filepath.Join(r.dir, name), data, 0o600); err != nil {
+		r.logger.Warn("envelope record failed", logging.KeyJobID, envelope.Ticket.JobID, logging.KeyError, err)
+	}
+}
+
+// safeFileName replaces characters that are unsafe in file names.
+func safeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,35 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Routing Reload (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// SIGHUP with SIGHUP_ACTION=reload swaps in the jobType allow/deny lists
+// and per-jobType caps from WORKER_CONFIG_FILE. The next claim uses the new
+// filter and caps; jobs already executing run to completion as claimed.
+// The capabilities sent at registration are not re-sent.
+
+package worker
+
+import (
+	"strings"
+
+	"github.com/gemimi2525-star/super-platform/worker/config"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// reloadRouting re-reads the routing settings, keeping the current ones if
+// the file can't be read or is invalid. Called from the poll loop, so it
+// never races a claim.
+func (w *Worker) reloadRouting() {
+	routing, err := config.ReloadRouting()
+	if err != nil {
+		w.logger.Error("jobType routing reload failed, keeping current routing", logging.KeyError, err)
+		return
+	}
+	w.routing.Store(&routing)
+	w.apiClient.SetJobTypeFilter(routing.JobTypeAllow, routing.JobTypeDeny)
+	w.logger.Info("jobType routing reloaded",
+		"allow", strings.Join(routing.JobTypeAllow, ","),
+		"deny", strings.Join(routing.JobTypeDeny, ","),
+		"concurrency", routing.JobTypeConcurrency,
+		"active", w.activeJobs())
+}
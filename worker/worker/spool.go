@@ -0,0 +1,218 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Result Delivery & Spool (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Result posts (success and failure) are retried with a capped backoff for
+// up to RESULT_POST_MAX_WAIT_SECONDS on transient failures. If TS still
+// can't be reached and RESULT_SPOOL_DIR is set, the signed result is
+// written there and re-posted once TS is back (at startup and every
+// spoolFlushInterval while running), so a short outage doesn't lose it.
+// TS dedupes by Idempotency-Key, so a late re-post is harmless.
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+	"github.com/gemimi2525-star/super-platform/worker/jobs"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// Result post retry backoff starts here and doubles up to RESULT_POST_BACKOFF_MAX_SECONDS.
+const resultPostBaseDelay = 500 * time.Millisecond
+
+// spoolFlushInterval is how often spooled results are re-posted.
+const spoolFlushInterval = 30 * time.Second
+
+// deliverResult runs post until it succeeds, fails permanently, or the
+// retry budget runs out; then it spools the result if a spool is
+// configured. posted is false when the result was spooled instead of
+// accepted by TS (err is nil in that case).
+func (w *Worker) deliverResult(ctx context.Context, result *contracts.JobResult, post func(context.Context) error) (posted bool, err error) {
+	giveUpAt := w.clock.Now().Add(w.config.ResultPostMaxWait)
+	for attempt := 0; ; attempt++ {
+		err = post(ctx)
+		if err == nil {
+			return true, nil
+		}
+		if !retryableDelivery(err) {
+			return false, err
+		}
+
+		delay := jobs.Backoff(resultPostBaseDelay, w.config.ResultPostBackoffMax, attempt)
+		if ctx.Err() != nil || w.clock.Now().Add(delay).After(giveUpAt) {
+			break
+		}
+		w.logger.Warn("result post failed, retrying",
+			logging.KeyJobID, result.JobID,
+			logging.KeyError, err,
+			"delay", delay.String())
+
+		// A ticker's first tick doubles as a timer on w.clock
+		wait := w.clock.NewTicker(delay)
+		select {
+		case <-ctx.Done():
+		case <-wait.C():
+		}
+		wait.Stop()
+	}
+
+	if w.spool == nil {
+		return false, err
+	}
+	if spoolErr := w.spool.save(result); spoolErr != nil {
+		return false, errors.Join(err, spoolErr)
+	}
+	w.logger.Warn("result post failed, spooled for later delivery",
+		logging.KeyJobID, result.JobID,
+		logging.KeyStatus, result.Status,
+		logging.KeyError, err)
+	return false, nil
+}
+
+// retryableDelivery reports whether a failed result post may succeed later:
+// transport errors, invalid acks, cancellation, 5xx and 429. Other TS
+// rejections and duplicate posts are final.
+func retryableDelivery(err error) bool {
+	if errors.Is(err, client.ErrResultAlreadyPosted) {
+		return false
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	return true
+}
+
+// spoolLoop re-posts spooled results until ctx is done.
+func (w *Worker) spoolLoop(ctx context.Context) {
+	ticker := time.NewTicker(spoolFlushInterval)
+	defer ticker.Stop()
+	for {
+		w.flushSpool(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// flushSpool re-posts spooled results in order, stopping at the first
+// transient failure (TS is probably still down).
+func (w *Worker) flushSpool(ctx context.Context) {
+	for _, path := range w.spool.pending() {
+		result, err := w.spool.load(path)
+		if err != nil {
+			w.logger.Error("spooled result unreadable, moving aside", "file", path, logging.KeyError, err)
+			w.spool.reject(path)
+			continue
+		}
+
+		disposition, err := w.apiClient.PostResult(ctx, result)
+		switch {
+		case err == nil:
+			w.recordResult(result)
+			w.spool.remove(path)
+			w.logger.Info("delivered spooled result", logging.KeyJobID, result.JobID, logging.KeyStatus, result.Status)
+			w.logDisposition(result, disposition)
+		case errors.Is(err, client.ErrResultAlreadyPosted):
+			w.spool.remove(path)
+		case retryableDelivery(err):
+			w.logger.Debug("spool flush deferred, TS still unavailable", logging.KeyError, err)
+			return
+		default:
+			w.logger.Error("TS rejected spooled result, moving aside", logging.KeyJobID, result.JobID, logging.KeyError, err)
+			w.spool.reject(path)
+		}
+	}
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// SPOOL DIRECTORY
+// ═══════════════════════════════════════════════════════════════════════════
+
+// resultSpool stores undelivered signed results, one JSON file per job
+// attempt (<jobId>-a<attempt>.json). Rejected files are renamed *.rejected.
+type resultSpool struct {
+	dir    string
+	logger *slog.Logger
+}
+
+// newResultSpool creates dir if needed.
+func newResultSpool(dir string, logger *slog.Logger) (*resultSpool, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create RESULT_SPOOL_DIR: %w", err)
+	}
+	return &resultSpool{dir: dir, logger: logger}, nil
+}
+
+// save writes result atomically (temp file + rename).
+func (s *resultSpool) save(result *contracts.JobResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal spooled result: %w", err)
+	}
+	name := fmt.Sprintf("%s-a%d.json", safeFileName(result.JobID), result.Metrics.Attempts)
+	tmp := filepath.Join(s.dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write spooled result: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write spooled result: %w", err)
+	}
+	return nil
+}
+
+// pending lists spooled result files, oldest name first.
+func (s *resultSpool) pending() []string {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		s.logger.Error("read result spool failed", logging.KeyError, err)
+		return nil
+	}
+	var paths []string
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".json") {
+			paths = append(paths, filepath.Join(s.dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (s *resultSpool) load(path string) (*contracts.JobResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var result contracts.JobResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *resultSpool) remove(path string) {
+	if err := os.Remove(path); err != nil {
+		s.logger.Error("remove spooled result failed", "file", path, logging.KeyError, err)
+	}
+}
+
+func (s *resultSpool) reject(path string) {
+	if err := os.Rename(path, path+".rejected"); err != nil {
+		s.logger.Error("move spooled result aside failed", "file", path, logging.KeyError, err)
+	}
+}
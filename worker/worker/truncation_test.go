@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+)
+
+func TestTruncatedResultSigning(t *testing.T) {
+	tests := []struct {
+		version       string
+		wantTruncated bool
+	}{
+		{"1", false},
+		{"2", true},
+	}
+	for _, tt := range tests {
+		t.Run("v"+tt.version, func(t *testing.T) {
+			t.Cleanup(func() { contracts.ResultSignatureVersion = contracts.ResultSignatureV1 })
+			w, ts, _ := newFakeClockWorker(t, map[string]string{
+				"MAX_RESULT_BYTES":         "64",
+				"RESULT_SIGNATURE_VERSION": tt.version,
+			})
+			if err := w.RegisterHandler("test.large_result", func(context.Context, string, string) (any, error) {
+				return map[string]any{"blob": strings.Repeat("x", 256)}, nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			// RunLocal returns the result as signed: the mock only verifies
+			// TS's v1 field set
+			envelope := ts.Envelope("test.large_result", `{}`)
+			posted, outcome, err := w.RunLocal(context.Background(), &envelope)
+			if err != nil || outcome.Status != "SUCCEEDED" || posted == nil {
+				t.Fatalf("RunLocal() = %v, %+v, %v, want a SUCCEEDED result", posted, outcome, err)
+			}
+			result := *posted
+			if summary, _ := result.ResultData.(map[string]any); summary["truncated"] != true {
+				t.Fatalf("resultData = %v, want the truncation summary", result.ResultData)
+			}
+			if result.Truncated != tt.wantTruncated {
+				t.Fatalf("posted truncated = %v, want %v", result.Truncated, tt.wantTruncated)
+			}
+			if err := result.Verify(ts.HMACSecret); err != nil {
+				t.Fatalf("Verify() = %v", err)
+			}
+
+			// The flag must be covered by the signature wherever it is sent; under
+			// v1 the summary's marker is covered through resultHash instead
+			tampered := result
+			tampered.Truncated = !result.Truncated
+			if err := tampered.Verify(ts.HMACSecret); tt.wantTruncated && err == nil {
+				t.Fatal("Verify() = nil after flipping the signed truncated flag")
+			}
+			if !tt.wantTruncated {
+				hash, err := contracts.ComputeResultHash(result.ResultData)
+				if err != nil || hash != result.ResultHash {
+					t.Fatalf("resultHash = %s, want the hash of the posted summary %s (%v)", result.ResultHash, hash, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — In-Flight Job WAL (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// Optional crash recovery (CRASH_RECOVERY=true). Claimed jobs are appended
+// to a JSON-lines write-ahead log under STATE_DIR before dispatch and marked
+// done once their result is posted. On startup, any job still pending is
+// reported FAILED/INTERRUPTED so TS requeues it without waiting for the
+// lease to expire.
+
+package worker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/logging"
+)
+
+// walFileName is the WAL file inside STATE_DIR.
+const walFileName = "inflight.wal"
+
+// walCompactBytes is the log size past which a done record triggers a
+// compaction down to the pending claims.
+const walCompactBytes = 1 << 20
+
+// WAL record operations.
+const (
+	walOpClaim = "claim"
+	walOpDone  = "done"
+)
+
+// walEntry is one JSON line in the WAL. Claim records carry enough of the
+// envelope to report a failure; done records only need the jobId.
+type walEntry struct {
+	Op          string `json:"op"`
+	JobID       string `json:"jobId"`
+	JobType     string `json:"jobType,omitempty"`
+	TraceID     string `json:"traceId,omitempty"`
+	Attempts    int    `json:"attempts,omitempty"`
+	MaxAttempts int    `json:"maxAttempts,omitempty"`
+	ClaimedAt   int64  `json:"claimedAt,omitempty"`
+}
+
+// envelope rebuilds the minimal envelope needed by reportFailure.
+func (e walEntry) envelope() *client.JobEnvelope {
+	env := &client.JobEnvelope{
+		Attempts:    e.Attempts,
+		MaxAttempts: e.MaxAttempts,
+	}
+	env.Ticket.JobID = e.JobID
+	env.Ticket.JobType = e.JobType
+	env.Ticket.TraceID = e.TraceID
+	return env
+}
+
+// jobWAL is an append-only log of in-flight jobs. It is truncated whenever
+// no job is pending, and compacted to the pending claims once it passes
+// walCompactBytes, so it stays small even when jobs are always in flight.
+type jobWAL struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	size    int64 // bytes in file
+	pending map[string]walEntry
+}
+
+// openJobWAL opens (creating if needed) the WAL in dir and returns it along
+// with the jobs left pending by a previous process.
+func openJobWAL(dir string) (*jobWAL, []walEntry, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, fmt.Errorf("create STATE_DIR: %w", err)
+	}
+	path := filepath.Join(dir, walFileName)
+
+	pending, err := readWAL(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open WAL: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("stat WAL: %w", err)
+	}
+
+	recovered := make([]walEntry, 0, len(pending))
+	for _, e := range pending {
+		recovered = append(recovered, e)
+	}
+	return &jobWAL{path: path, file: file, size: info.Size(), pending: pending}, recovered, nil
+}
+
+// readWAL replays the log into the set of claimed-but-not-done jobs.
+// A torn last line (crash mid-write) is ignored.
+func readWAL(path string) (map[string]walEntry, error) {
+	pending := make(map[string]walEntry)
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return pending, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open WAL: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e walEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		switch e.Op {
+		case walOpClaim:
+			pending[e.JobID] = e
+		case walOpDone:
+			delete(pending, e.JobID)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read WAL: %w", err)
+	}
+	return pending, nil
+}
+
+// claim records a job claimed at claimedAt before dispatch.
+func (l *jobWAL) claim(envelope *client.JobEnvelope, claimedAt time.Time) error {
+	e := walEntry{
+		Op:          walOpClaim,
+		JobID:       envelope.Ticket.JobID,
+		JobType:     envelope.Ticket.JobType,
+		TraceID:     envelope.Ticket.TraceID,
+		Attempts:    envelope.Attempts,
+		MaxAttempts: envelope.MaxAttempts,
+		ClaimedAt:   claimedAt.UnixMilli(),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending[e.JobID] = e
+	return l.append(e)
+}
+
+// done marks a job as finished (result posted or lease released).
+func (l *jobWAL) done(jobID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.pending[jobID]; !ok {
+		return nil
+	}
+	delete(l.pending, jobID)
+
+	// Nothing in flight: start over with an empty log
+	if len(l.pending) == 0 {
+		if err := l.file.Truncate(0); err != nil {
+			return fmt.Errorf("truncate WAL: %w", err)
+		}
+		l.size = 0
+		return nil
+	}
+	if l.size >= walCompactBytes {
+		err := l.compact()
+		if err == nil {
+			return nil
+		}
+		// Still on the old log: record the done there
+		return errors.Join(err, l.append(walEntry{Op: walOpDone, JobID: jobID}))
+	}
+	return l.append(walEntry{Op: walOpDone, JobID: jobID})
+}
+
+// rewrite replaces the log with only the given entries (used after
+// recovery to keep jobs whose failure report could not be posted).
+func (l *jobWAL) rewrite(entries []walEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending = make(map[string]walEntry, len(entries))
+	for _, e := range entries {
+		l.pending[e.JobID] = e
+	}
+	return l.compact()
+}
+
+// compact replaces the log with one claim record per pending job. The new
+// log is written beside the old one and renamed over it, so a crash
+// mid-compaction leaves one or the other intact. Caller holds mu.
+func (l *jobWAL) compact() error {
+	tmpPath := l.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("compact WAL: %w", err)
+	}
+	old := l.file
+	l.file, l.size = file, 0
+	for _, e := range l.pending {
+		if err = l.append(e); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, l.path)
+	}
+	if err != nil {
+		// Keep appending to the old log, which still has every record
+		file.Close()
+		os.Remove(tmpPath)
+		l.file = old
+		if info, statErr := old.Stat(); statErr == nil {
+			l.size = info.Size()
+		}
+		return fmt.Errorf("compact WAL: %w", err)
+	}
+	old.Close()
+	return nil
+}
+
+// append writes one record. Caller holds mu. O_APPEND keeps writes at the
+// end of the file even after Truncate.
+func (l *jobWAL) append(e walEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	n, err := l.file.Write(append(line, '\n'))
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write WAL: %w", err)
+	}
+	return nil
+}
+
+// close closes the log file.
+func (l *jobWAL) close() error {
+	return l.file.Close()
+}
+
+// recoverInterrupted reports jobs left pending by a crashed process as
+// FAILED/INTERRUPTED. Jobs whose report cannot be posted stay in the WAL
+// and are retried on the next start.
+func (w *Worker) recoverInterrupted(entries []walEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var remaining []walEntry
+	for _, e := range entries {
+		_, err := w.reportFailure(ctx, e.envelope(), "INTERRUPTED", "worker restarted while job was in flight")
+		if err != nil {
+			w.logger.Error("interrupted job report failed", logging.KeyJobID, e.JobID, logging.KeyError, err)
+			remaining = append(remaining, e)
+			continue
+		}
+		w.logger.Warn("reported interrupted job from previous run",
+			logging.KeyJobID, e.JobID,
+			logging.KeyJobType, e.JobType,
+			logging.KeyAttempt, e.Attempts,
+			logging.KeyStatus, "INTERRUPTED")
+	}
+
+	if err := w.wal.rewrite(remaining); err != nil {
+		w.logger.Error("WAL rewrite failed", logging.KeyError, err)
+	}
+}
+
+// walDone marks a job finished in the WAL, if crash recovery is enabled.
+func (w *Worker) walDone(jobID string) {
+	if w.wal == nil {
+		return
+	}
+	if err := w.wal.done(jobID); err != nil {
+		w.logger.Error("WAL write failed", logging.KeyJobID, jobID, logging.KeyError, err)
+	}
+}
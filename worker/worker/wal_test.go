@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+)
+
+func TestJobWALCompactsWhileJobsStayPending(t *testing.T) {
+	dir := t.TempDir()
+	wal, _, err := openJobWAL(dir)
+	if err != nil {
+		t.Fatalf("openJobWAL() = %v", err)
+	}
+	defer wal.close()
+
+	envelope := func(jobID string) *client.JobEnvelope {
+		e := &client.JobEnvelope{Attempts: 1, MaxAttempts: 3}
+		e.Ticket.JobID = jobID
+		e.Ticket.JobType = "scheduler.tick"
+		return e
+	}
+
+	// One long-running job keeps the log from ever being empty
+	if err := wal.claim(envelope("long-running"), time.Now()); err != nil {
+		t.Fatalf("claim() = %v", err)
+	}
+	path := filepath.Join(dir, walFileName)
+	for i := 0; i < 20000; i++ {
+		jobID := fmt.Sprintf("job-%d", i)
+		if err := wal.claim(envelope(jobID), time.Now()); err != nil {
+			t.Fatalf("claim() = %v", err)
+		}
+		if err := wal.done(jobID); err != nil {
+			t.Fatalf("done() = %v", err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat WAL: %v", err)
+	}
+	if info.Size() > walCompactBytes+4096 {
+		t.Fatalf("WAL is %d bytes, want it compacted below %d", info.Size(), walCompactBytes)
+	}
+
+	pending, err := readWAL(path)
+	if err != nil {
+		t.Fatalf("readWAL() = %v", err)
+	}
+	if len(pending) != 1 || pending["long-running"].JobID != "long-running" {
+		t.Fatalf("pending after compaction = %v, want only long-running", pending)
+	}
+}
+
+func TestAdmitJobWALAfterResultRejection(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantEntry bool
+	}{
+		{"accepted", 0, false},
+		{"rejected", http.StatusBadRequest, false},
+		{"unavailable", http.StatusServiceUnavailable, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, ts, _ := newFakeClockWorker(t, map[string]string{
+				"CRASH_RECOVERY":               "true",
+				"STATE_DIR":                    t.TempDir(),
+				"HTTP_MAX_RETRIES":             "0",
+				"RESULT_POST_MAX_WAIT_SECONDS": "0",
+			})
+			ts.SetStatus("/api/jobs/result", tt.status)
+
+			envelope := ts.Envelope("scheduler.tick", `{}`)
+			w.admitJob(context.Background(), &envelope)()
+			w.wal.mu.Lock()
+			_, pending := w.wal.pending[envelope.Ticket.JobID]
+			w.wal.mu.Unlock()
+			if pending != tt.wantEntry {
+				t.Fatalf("WAL entry kept = %v, want %v", pending, tt.wantEntry)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+// ═══════════════════════════════════════════════════════════════════════════
+// CORE OS — Mock TS Core OS (Phase 22C)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// MockCoreOS is an in-process TS for end-to-end worker tests. It serves
+// the claim, heartbeat, progress, result, release and registration
+// endpoints from a queue of envelopes, signs tickets with TestKey, checks
+// results the way TS does (its v1 signer field set, SUCCEEDED or FAILED
+// only) and records everything the worker posts:
+//
+//	ts := workertest.NewMockCoreOS()
+//	defer ts.Close()
+//	for k, v := range ts.Env() {
+//		t.Setenv(k, v)
+//	}
+//	cfg, _ := config.Load()
+//	w, _ := worker.New(cfg, logger)
+//	envelope := ts.Envelope("scheduler.tick", `{}`)
+//	w.ProcessJob(ctx, &envelope)
+//	result, ok := ts.Result(envelope.Ticket.JobID)
+//
+// Endpoints it doesn't implement (long-poll, chunked results, peek) answer
+// 404, which the client treats as "not supported" and falls back from.
+
+package workertest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/gemimi2525-star/super-platform/worker/client"
+	"github.com/gemimi2525-star/super-platform/worker/clock"
+	"github.com/gemimi2525-star/super-platform/worker/contracts"
+)
+
+// DefaultHMACSecret is the JOB_WORKER_HMAC_SECRET Env returns.
+const DefaultHMACSecret = "workertest-hmac-secret"
+
+// mockLease is the lease each heartbeat extends a job by.
+const mockLease = 30 * time.Second
+
+// MockCoreOS is a fake TS server. Its methods are safe for concurrent use.
+type MockCoreOS struct {
+	// URL is the server's base URL (COREOS_API_URL).
+	URL string
+
+	// HMACSecret verifies posted results and signs result acks.
+	HMACSecret string
+
+	// Clock dates the envelopes Envelope returns and heartbeat leases
+	// (clock.Real; set it to the clock passed to Worker.SetClock).
+	Clock clock.Clock
+
+	server *httptest.Server
+
+	mu          sync.Mutex
+	maxAttempts map[string]int // jobId → envelope MaxAttempts, for dispositions
+	queue       []client.JobEnvelope
+	results     []contracts.JobResult
+	rejected    []contracts.JobResult
+	heartbeats  []string
+	progress    map[string][]json.RawMessage // jobId → checkpoints, in order
+	released    []string
+	cancel      map[string]string // jobId → reason, answered on its next heartbeat (hypothetical, see CancelJob)
+	statuses    map[string]int    // path → forced status code
+}
+
+// NewMockCoreOS starts a mock TS on a local port. Call Close when done.
+func NewMockCoreOS() *MockCoreOS {
+	m := &MockCoreOS{
+		HMACSecret:  DefaultHMACSecret,
+		Clock:       clock.Real,
+		maxAttempts: make(map[string]int),
+		cancel:      make(map[string]string),
+		progress:    make(map[string][]json.RawMessage),
+		statuses:    make(map[string]int),
+	}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serve))
+	m.URL = m.server.URL
+	return m
+}
+
+// Close shuts the server down.
+func (m *MockCoreOS) Close() {
+	m.server.Close()
+}
+
+// Env returns the environment config.Load needs to run against this mock.
+func (m *MockCoreOS) Env() map[string]string {
+	return map[string]string{
+		"COREOS_API_URL":         m.URL,
+		"JOB_WORKER_HMAC_SECRET": m.HMACSecret,
+		"JOB_TICKET_PUBLIC_KEY":  TestPublicKey(),
+	}
+}
+
+// Envelope returns a valid envelope signed with TestKey (see NewEnvelope).
+// It is not queued; pass it to ProcessJob directly or to Enqueue (which
+// also picks up a changed MaxAttempts for result dispositions).
+func (m *MockCoreOS) Envelope(jobType, payload string) client.JobEnvelope {
+	envelope := NewEnvelopeAt(TestKey, jobType, payload, m.Clock.Now())
+	m.track(envelope)
+	return envelope
+}
+
+// Enqueue adds envelopes to be handed out by the claim endpoints, in order.
+func (m *MockCoreOS) Enqueue(envelopes ...client.JobEnvelope) {
+	for _, envelope := range envelopes {
+		m.track(envelope)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = append(m.queue, envelopes...)
+}
+
+// track remembers an envelope's MaxAttempts for the disposition of its
+// results.
+func (m *MockCoreOS) track(envelope client.JobEnvelope) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxAttempts[envelope.Ticket.JobID] = envelope.MaxAttempts
+}
+
+// Pending returns the number of queued envelopes not yet claimed.
+func (m *MockCoreOS) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.queue)
+}
+
+// Results returns the results accepted so far, in the order posted.
+func (m *MockCoreOS) Results() []contracts.JobResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]contracts.JobResult(nil), m.results...)
+}
+
+// Result returns the last accepted result for jobID.
+func (m *MockCoreOS) Result(jobID string) (contracts.JobResult, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.results) - 1; i >= 0; i-- {
+		if m.results[i].JobID == jobID {
+			return m.results[i], true
+		}
+	}
+	return contracts.JobResult{}, false
+}
+
+// Rejected returns results refused because their signature didn't verify
+// with HMACSecret (401) or their status isn't SUCCEEDED or FAILED (403).
+func (m *MockCoreOS) Rejected() []contracts.JobResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]contracts.JobResult(nil), m.rejected...)
+}
+
+// Heartbeats returns the jobId of every heartbeat received, in order.
+func (m *MockCoreOS) Heartbeats() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.heartbeats...)
+}
+
+// Checkpoints returns the progress checkpoints posted for jobID, in order.
+func (m *MockCoreOS) Checkpoints(jobID string) []json.RawMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]json.RawMessage(nil), m.progress[jobID]...)
+}
+
+// Released returns the jobId of every lease release received, in order.
+func (m *MockCoreOS) Released() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.released...)
+}
+
+// CancelJob makes the next heartbeat for jobID ask the worker to cancel it.
+// Hypothetical: TS's heartbeat response is only {jobId, leaseUntil} and has
+// no cancel flag; this exercises the worker's support for one ahead of TS.
+func (m *MockCoreOS) CancelJob(jobID, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancel[jobID] = reason
+}
+
+// SetStatus makes every request to path (e.g. "/api/jobs/result") fail
+// with status; 0 restores normal handling.
+func (m *MockCoreOS) SetStatus(path string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if status == 0 {
+		delete(m.statuses, path)
+		return
+	}
+	m.statuses[path] = status
+}
+
+func (m *MockCoreOS) serve(rw http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	status := m.statuses[r.URL.Path]
+	m.mu.Unlock()
+	if status != 0 {
+		http.Error(rw, "forced by MockCoreOS.SetStatus", status)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api/jobs/claim":
+		var job any
+		if jobs := m.take(1); len(jobs) == 1 {
+			job = jobs[0]
+		}
+		writeJSON(rw, map[string]any{"job": job})
+	case "/api/jobs/claim-batch":
+		var req struct {
+			Max int `json:"max"`
+		}
+		json.Unmarshal(body, &req)
+		writeJSON(rw, map[string]any{"jobs": m.take(max(req.Max, 1))})
+	case "/api/jobs/heartbeat":
+		var req struct {
+			JobID string `json:"jobId"`
+		}
+		json.Unmarshal(body, &req)
+		m.mu.Lock()
+		m.heartbeats = append(m.heartbeats, req.JobID)
+		reason, cancel := m.cancel[req.JobID]
+		m.mu.Unlock()
+		resp := map[string]any{"jobId": req.JobID, "leaseUntil": m.Clock.Now().Add(mockLease).UnixMilli()}
+		if cancel {
+			resp["cancel"] = true
+			resp["reason"] = reason
+		}
+		writeJSON(rw, resp)
+	case "/api/jobs/progress":
+		var req struct {
+			JobID      string          `json:"jobId"`
+			Checkpoint json.RawMessage `json:"checkpoint"`
+		}
+		json.Unmarshal(body, &req)
+		m.mu.Lock()
+		m.progress[req.JobID] = append(m.progress[req.JobID], req.Checkpoint)
+		m.mu.Unlock()
+		writeJSON(rw, map[string]any{"ok": true})
+	case "/api/jobs/result":
+		m.serveResult(rw, body)
+	case "/api/jobs/release":
+		var req struct {
+			JobID string `json:"jobId"`
+		}
+		json.Unmarshal(body, &req)
+		m.mu.Lock()
+		m.released = append(m.released, req.JobID)
+		m.mu.Unlock()
+		writeJSON(rw, map[string]any{"ok": true})
+	case "/api/workers/register", "/api/workers/deregister":
+		writeJSON(rw, map[string]any{"ok": true})
+	case "/api/keys":
+		writeJSON(rw, client.KeysResponse{Keys: []string{TestPublicKey()}})
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+// serveResult records a posted result and answers with a signed ack and
+// a disposition, 403 INVALID_STATUS for a status TS doesn't accept, or 401
+// if the result signature is invalid.
+func (m *MockCoreOS) serveResult(rw http.ResponseWriter, body []byte) {
+	var result contracts.JobResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if result.Status != "SUCCEEDED" && result.Status != "FAILED" {
+		m.reject(result)
+		http.Error(rw, "INVALID_STATUS: status must be SUCCEEDED or FAILED", http.StatusForbidden)
+		return
+	}
+	if err := contracts.VerifyHMAC(m.HMACSecret, tsSignable(result), result.Signature); err != nil {
+		m.reject(result)
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	m.mu.Lock()
+	m.results = append(m.results, result)
+	maxAttempts, known := m.maxAttempts[result.JobID]
+	m.mu.Unlock()
+	if !known {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	ack := contracts.ResultAck{JobID: result.JobID, Nonce: newNonce()}
+	signable, _ := json.Marshal(map[string]string{"jobId": ack.JobID, "nonce": ack.Nonce})
+	ack.Signature = contracts.ComputeHMAC(m.HMACSecret, string(signable))
+	writeJSON(rw, client.ResultResponse{Ack: &ack, Disposition: disposition(result, maxAttempts)})
+}
+
+// reject records a refused result.
+func (m *MockCoreOS) reject(result contracts.JobResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rejected = append(m.rejected, result)
+}
+
+// tsSignable is the canonical JSON TS's result signer covers: exactly
+// jobId, status, startedAt, finishedAt, resultHash, traceId, workerId and
+// metrics, with keys sorted. It is rebuilt here rather than taken from
+// contracts so a worker-side drift from TS shows up as a 401.
+func tsSignable(result contracts.JobResult) string {
+	b, _ := json.Marshal(struct {
+		FinishedAt int64                `json:"finishedAt"`
+		JobID      string               `json:"jobId"`
+		Metrics    contracts.JobMetrics `json:"metrics"`
+		ResultHash string               `json:"resultHash"`
+		StartedAt  int64                `json:"startedAt"`
+		Status     string               `json:"status"`
+		TraceID    string               `json:"traceId"`
+		WorkerID   string               `json:"workerId"`
+	}{result.FinishedAt, result.JobID, result.Metrics, result.ResultHash, result.StartedAt, result.Status, result.TraceID, result.WorkerID})
+	return string(b)
+}
+
+// disposition is what a real TS would report: FAILED results are retried
+// while attempts < maxAttempts, and dead-lettered after. Like TS, it
+// ignores the worker's giveUp hint.
+func disposition(result contracts.JobResult, maxAttempts int) string {
+	switch {
+	case result.Status != "FAILED":
+		return client.DispositionAccepted
+	case result.Metrics.Attempts < maxAttempts:
+		return client.DispositionRetryScheduled
+	default:
+		return client.DispositionDeadLettered
+	}
+}
+
+// take removes and returns up to n queued envelopes.
+func (m *MockCoreOS) take(n int) []client.JobEnvelope {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n = min(n, len(m.queue))
+	jobs := append([]client.JobEnvelope(nil), m.queue[:n]...)
+	m.queue = m.queue[n:]
+	return jobs
+}
+
+// readBody reads the request body, gunzipping it if the worker compressed it.
+func readBody(r *http.Request) ([]byte, error) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	return io.ReadAll(body)
+}
+
+func writeJSON(rw http.ResponseWriter, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(v)
+}